@@ -0,0 +1,212 @@
+package main
+
+import (
+	"SongLibrary/config"
+	"SongLibrary/pkg/fakeinfo"
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestE2EAddSong runs server with in-memory storage which enriches new songs from fakeinfo.Server
+// serving testdata/e2e_fixture.json
+func TestE2EAddSong(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("end-to-end test waits for enrichment retries")
+	}
+
+	fixture, err := fakeinfo.ReadFixture("testdata/e2e_fixture.json")
+	if err != nil {
+		t.Fatalf("read fixture error: %v", err)
+	}
+	infoServer := httptest.NewServer((&fakeinfo.Server{Fixture: fixture}).Mux())
+	t.Cleanup(infoServer.Close)
+
+	//retries are short and breaker never opens, so every case ends quickly and independently
+	cfg := &config.Config{
+		InfoProviders:       infoServer.URL + "|1s",
+		InfoBreakerFailures: 100,
+		EnrichPollInterval:  100 * time.Millisecond,
+		EnrichMaxAttempts:   2,
+		EnrichBackoff:       100 * time.Millisecond,
+	}
+	setEnrichDefaults(cfg)
+	setTrashDefaults(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	mux, err := newMux(ctx, cfg, &song.SongHandler{Storage: storage.NewMemoryStorage()})
+	if err != nil {
+		t.Fatalf("new mux error: %v", err)
+	}
+	api := httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+
+	tests := []struct {
+		name         string
+		song         string
+		wantStatus   string
+		wantAttempts int
+		wantError    string
+	}{
+		{"success", "Supermassive Black Hole", song.EnrichmentDone, 1, ""},
+		{"404 fails without retries", "Unknown Song", song.EnrichmentFailed, 1, ""},
+		{"500 fails after retries", "Hysteria", song.EnrichmentFailed, 2, "500"},
+		{"malformed json fails after retries", "Starlight", song.EnrichmentFailed, 2, "unmarshal"},
+		{"slow answer within timeout", "Uprising", song.EnrichmentDone, 1, ""},
+		{"slow answer times out", "Plug In Baby", song.EnrichmentFailed, 2, "deadline exceeded"},
+	}
+
+	ids := make(map[string]int)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			id := addSong(t, api.URL, tt.song, "Muse")
+			ids[tt.song] = id
+
+			enrichment := waitEnrichment(t, api.URL, id)
+			if enrichment.Status != tt.wantStatus || enrichment.Attempts != tt.wantAttempts || !strings.Contains(enrichment.LastError, tt.wantError) {
+				t.Errorf("got %+v, want status %s, attempts %d, error with [%s]", *enrichment, tt.wantStatus, tt.wantAttempts, tt.wantError)
+			}
+		})
+	}
+
+	t.Run("success fields", func(t *testing.T) {
+
+		var list struct {
+			Response []*song.Song `json:"response"`
+		}
+		query := url.Values{"song": {"Supermassive Black Hole"}, "group": {"Muse"}}
+		getJSON(t, api.URL+"/api/songs?"+query.Encode(), &list)
+		if len(list.Response) != 1 {
+			t.Fatalf("got %d songs, want 1", len(list.Response))
+		}
+		item := list.Response[0]
+		if item.ReleaseDate != "16.07.2006" || item.Link != "https://www.youtube.com/watch?v=Xsp3_a-PMTw" {
+			t.Errorf("got %+v", *item)
+		}
+
+		var verses struct {
+			Response struct {
+				VersesInSong int           `json:"versesInSong"`
+				Items        []*song.Verse `json:"items"`
+			} `json:"response"`
+		}
+		getJSON(t, fmt.Sprintf("%s/api/songs/%d", api.URL, ids["Supermassive Black Hole"]), &verses)
+		if verses.Response.VersesInSong != 2 || len(verses.Response.Items) != 2 || verses.Response.Items[1].Kind != song.VerseKindChorus {
+			t.Errorf("got verses %+v", verses.Response)
+		}
+	})
+}
+
+// addSong returns id of new song, request must not wait for info API
+// TestE2ENoInfoProviders checks that server starts without enrichment when no info API is configured
+func TestE2ENoInfoProviders(t *testing.T) {
+
+	cfg := &config.Config{}
+	setEnrichDefaults(cfg)
+	setTrashDefaults(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	mux, err := newMux(ctx, cfg, &song.SongHandler{Storage: storage.NewMemoryStorage()})
+	if err != nil {
+		t.Fatalf("new mux error: %v", err)
+	}
+	api := httptest.NewServer(mux)
+	t.Cleanup(api.Close)
+
+	id := addSong(t, api.URL, "Uprising", "Muse")
+	resp, err := http.Get(fmt.Sprintf("%s/api/songs/%d/enrichment", api.URL, id))
+	if err != nil {
+		t.Fatalf("get enrichment error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("enrichment status is %d, want 404 without enrichment routes", resp.StatusCode)
+	}
+}
+
+func addSong(t *testing.T, apiURL, name, group string) int {
+	t.Helper()
+
+	body, err := json.Marshal(song.Song{Name: name, Group: group})
+	if err != nil {
+		t.Fatalf("marshal song error: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPut, apiURL+"/api/songs", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request error: %v", err)
+	}
+
+	started := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("add [%s] error: %v", name, err)
+	}
+	defer resp.Body.Close()
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("add [%s] took %s", name, elapsed)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add [%s]: got status %d, want %d", name, resp.StatusCode, http.StatusCreated)
+	}
+
+	var answer struct {
+		Response struct {
+			ID int `json:"id"`
+		} `json:"response"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&answer)
+	if err != nil {
+		t.Fatalf("decode answer error: %v", err)
+	}
+
+	return answer.Response.ID
+}
+
+// waitEnrichment returns enrichment of song after it is done or failed
+func waitEnrichment(t *testing.T, apiURL string, id int) *song.Enrichment {
+	t.Helper()
+
+	var answer struct {
+		Response *song.Enrichment `json:"response"`
+	}
+	for i := 0; i < 100; i++ {
+		getJSON(t, fmt.Sprintf("%s/api/songs/%d/enrichment", apiURL, id), &answer)
+		if answer.Response.Status == song.EnrichmentDone || answer.Response.Status == song.EnrichmentFailed {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return answer.Response
+}
+
+func getJSON(t *testing.T, url string, target interface{}) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("get [%s] error: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get [%s]: got status %d", url, resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(target)
+	if err != nil {
+		t.Fatalf("decode [%s] error: %v", url, err)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"SongLibrary/pkg/fakeinfo"
+	"flag"
+	"log"
+	"net/http"
+)
+
+// fakeinfo serves fakeinfo.Server, see pkg/fakeinfo
+func main() {
+
+	port := flag.String("port", "8082", "port to listen")
+	path := flag.String("fixture", "cmd/fakeinfo/fixture.yaml", "json or yaml file with songs")
+	flag.Parse()
+
+	fixture, err := fakeinfo.ReadFixture(*path)
+	if err != nil {
+		log.Println("reading fixture file failed:", err.Error())
+		return
+	}
+
+	server := &fakeinfo.Server{
+		Fixture: fixture,
+	}
+
+	log.Printf("start fake info API at %s, songs: [%d]\n", *port, len(fixture.Songs))
+	http.ListenAndServe(":"+*port, server.Mux())
+}
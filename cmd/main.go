@@ -2,20 +2,30 @@ package main
 
 import (
 	"SongLibrary/config"
+	"SongLibrary/pkg/album"
+	"SongLibrary/pkg/enrich"
+	"SongLibrary/pkg/group"
+	"SongLibrary/pkg/info"
 	"SongLibrary/pkg/song"
 	"SongLibrary/pkg/storage"
+	"SongLibrary/pkg/tag"
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	_ "SongLibrary/docs"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
+	_ "modernc.org/sqlite"
 )
 
 // @title SongLibrary Swagger API
@@ -23,12 +33,316 @@ import (
 // @description API for song library
 func main() {
 
+	memory := flag.Bool("memory", false, "keep songs in memory instead of postgres, data is lost on exit")
+	flag.Parse()
+
 	cfg, err := config.ReadConfig("app", "./config")
 	if err != nil {
 		log.Println("reading config file failed")
 		return
 	}
 
+	if cfg.FuzzyThreshold == 0 {
+		cfg.FuzzyThreshold = 0.3
+	}
+	setEnrichDefaults(cfg)
+	setTrashDefaults(cfg)
+
+	songHandler := &song.SongHandler{
+		FuzzyThreshold:  cfg.FuzzyThreshold,
+		ImportBatchSize: cfg.ImportBatchSize,
+		RequireIfMatch:  cfg.RequireIfMatch,
+	}
+
+	switch {
+	case *memory:
+		songHandler.Storage = storage.NewMemoryStorage()
+		log.Println("in-memory storage is used")
+
+	case cfg.DBDriver == "sqlite":
+		db, err := openSQLite(cfg)
+		if err != nil {
+			return
+		}
+		defer db.Close()
+
+		songHandler.Storage = storage.NewSQLiteStorage(db)
+
+	case cfg.DBDriver == "postgres" || cfg.DBDriver == "":
+		db, err := openPostgres(cfg)
+		if err != nil {
+			return
+		}
+		defer db.Close()
+
+		songHandler.Storage = storage.NewStorage(db)
+
+	default:
+		log.Println("unknown db driver:", cfg.DBDriver)
+		return
+	}
+
+	mux, err := newMux(context.Background(), cfg, songHandler)
+	if err != nil {
+		log.Println("server setup failed:", err.Error())
+		return
+	}
+
+	log.Println("start server at", cfg.HTTPPort)
+	http.ListenAndServe(":"+cfg.HTTPPort, mux)
+
+}
+
+// newMux registers routes of storage capabilities and starts background jobs of enrichment,
+// refresh and trash purge until ctx is done
+func newMux(ctx context.Context, cfg *config.Config, songHandler *song.SongHandler) (*http.ServeMux, error) {
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /swagger/", swaggerHandler)
+	//v1 routes of songs are replaced by /api/v2
+	mux.HandleFunc("GET /api/songs", song.Deprecated(songHandler.GetAll, v1DeprecatedAt, v1Sunset, "/api/v2/songs"))
+	mux.HandleFunc("GET /api/songs/search", songHandler.Search)
+	mux.HandleFunc("POST /api/songs/import", songHandler.Import)
+	mux.HandleFunc("GET /api/songs/export", songHandler.Export)
+	mux.HandleFunc("GET /api/songs/{id}", song.Deprecated(songHandler.Get, v1DeprecatedAt, v1Sunset, "/api/v2/songs/{id}"))
+	mux.HandleFunc("GET /api/songs/{id}/verses/{position}", songHandler.GetVerse)
+	mux.HandleFunc("POST /api/songs/{id}/verses/{position}", songHandler.UpdateVerse)
+	mux.HandleFunc("PUT /api/songs", song.Deprecated(songHandler.New, v1DeprecatedAt, v1Sunset, "/api/v2/songs"))
+	mux.HandleFunc("POST /api/songs", song.Deprecated(songHandler.Update, v1DeprecatedAt, v1Sunset, "/api/v2/songs"))
+	mux.HandleFunc("DELETE /api/songs", song.Deprecated(songHandler.Delete, v1DeprecatedAt, v1Sunset, "/api/v2/songs"))
+	mux.HandleFunc("GET /api/songs/{id}/revisions", songHandler.GetRevisions)
+	mux.HandleFunc("GET /api/songs/{id}/revisions/diff", songHandler.DiffRevisions)
+	mux.HandleFunc("GET /api/songs/{id}/revisions/{revision}", songHandler.GetRevision)
+	mux.HandleFunc("POST /api/songs/{id}/revisions/{revision}/restore", songHandler.RestoreRevision)
+
+	v2Handler := &song.V2Handler{
+		SongHandler: songHandler,
+	}
+	mux.HandleFunc("GET /api/v2/songs", v2Handler.GetAll)
+	mux.HandleFunc("POST /api/v2/songs", v2Handler.New)
+	mux.HandleFunc("GET /api/v2/songs/{id}", v2Handler.Get)
+	mux.HandleFunc("PUT /api/v2/songs/{id}", v2Handler.Replace)
+	mux.HandleFunc("PATCH /api/v2/songs/{id}", v2Handler.Patch)
+	mux.HandleFunc("DELETE /api/v2/songs/{id}", v2Handler.Delete)
+
+	if trash, ok := songHandler.Storage.(song.Trash); ok {
+		purger := &song.TrashPurger{
+			Storage:   trash,
+			Retention: time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour,
+			Interval:  cfg.TrashPurgeInterval,
+		}
+		go purger.Run(ctx)
+
+		mux.HandleFunc("GET /api/trash", songHandler.GetTrash)
+		mux.HandleFunc("POST /api/trash/{id}/restore", songHandler.RestoreTrashed)
+		mux.HandleFunc("DELETE /api/trash/{id}", songHandler.Purge)
+	}
+
+	//groups are stored separately from songs only in postgres
+	if groupStorage, ok := songHandler.Storage.(group.Storage); ok {
+		groupHandler := &group.GroupHandler{
+			Storage: groupStorage,
+		}
+
+		mux.HandleFunc("GET /api/groups", groupHandler.GetAll)
+		mux.HandleFunc("GET /api/groups/{id}", groupHandler.Get)
+		mux.HandleFunc("GET /api/groups/{id}/songs", groupHandler.GetSongs)
+		mux.HandleFunc("PUT /api/groups", groupHandler.New)
+		mux.HandleFunc("POST /api/groups", groupHandler.Update)
+		mux.HandleFunc("DELETE /api/groups", groupHandler.Delete)
+	}
+
+	if albumStorage, ok := songHandler.Storage.(album.Storage); ok {
+		albumHandler := &album.AlbumHandler{
+			Storage: albumStorage,
+		}
+
+		mux.HandleFunc("GET /api/albums", albumHandler.GetAll)
+		mux.HandleFunc("GET /api/albums/{id}", albumHandler.Get)
+		mux.HandleFunc("PUT /api/albums", albumHandler.New)
+		mux.HandleFunc("DELETE /api/albums", albumHandler.Delete)
+		mux.HandleFunc("PUT /api/albums/{id}/songs", albumHandler.AttachSong)
+		mux.HandleFunc("POST /api/albums/{id}/songs", albumHandler.ReorderTracks)
+		mux.HandleFunc("DELETE /api/albums/{id}/songs", albumHandler.DetachSong)
+	}
+
+	if tagStorage, ok := songHandler.Storage.(tag.Storage); ok {
+		tagHandler := &tag.TagHandler{
+			Storage: tagStorage,
+		}
+
+		mux.HandleFunc("GET /api/tags", tagHandler.GetAll)
+		mux.HandleFunc("DELETE /api/tags", tagHandler.Delete)
+		mux.HandleFunc("GET /api/songs/{id}/tags", tagHandler.GetSongTags)
+		mux.HandleFunc("PUT /api/songs/{id}/tags", tagHandler.Attach)
+		mux.HandleFunc("DELETE /api/songs/{id}/tags", tagHandler.Detach)
+	}
+
+	enrichStorage, ok := actorStorage(songHandler.Storage, enrich.ActorEnrichment).(enrich.Storage)
+	if ok && cfg.InfoProviders == "" && cfg.ExternalAPI == "" {
+		//enrichment jobs of new songs wait in queue until info API is configured
+		log.Println("info providers are not configured, songs are not enriched")
+		ok = false
+	}
+	if ok {
+		provider, sources, err := infoProvider(cfg)
+		if err != nil {
+			log.Println("info providers config error:", err.Error())
+			return nil, err
+		}
+
+		worker := &enrich.Worker{
+			Storage:      enrichStorage,
+			Info:         provider,
+			PollInterval: cfg.EnrichPollInterval,
+			MaxAttempts:  cfg.EnrichMaxAttempts,
+			Backoff:      cfg.EnrichBackoff,
+			MaxBackoff:   cfg.EnrichMaxBackoff,
+			StaleAfter:   cfg.EnrichStaleAfter,
+		}
+		go worker.Run(ctx)
+
+		if refreshStorage, ok := actorStorage(songHandler.Storage, enrich.ActorRefresh).(enrich.RefreshStorage); ok {
+			scheduler := &enrich.Scheduler{
+				Storage:          refreshStorage,
+				Info:             provider,
+				Interval:         cfg.RefreshInterval,
+				MaxAge:           cfg.RefreshMaxAge,
+				IncompleteMinAge: cfg.RefreshIncompleteMinAge,
+				BatchSize:        cfg.RefreshBatchSize,
+				RateLimit:        cfg.RefreshRateLimit,
+			}
+			go scheduler.Run(ctx)
+
+			refreshHandler := &enrich.RefreshHandler{
+				Storage: refreshStorage,
+			}
+			mux.HandleFunc("GET /api/enrichment/runs", refreshHandler.GetRuns)
+		}
+
+		mux.HandleFunc("GET /api/songs/{id}/enrichment", songHandler.GetEnrichment)
+		mux.HandleFunc("POST /api/songs/{id}/enrichment", songHandler.RetryEnrichment)
+
+		breakerHandler := &info.BreakerHandler{
+			Sources: sources,
+		}
+		mux.HandleFunc("GET /api/enrichment/breakers", breakerHandler.GetAll)
+	}
+
+	return mux, nil
+}
+
+// v1 song routes are deprecated in favour of /api/v2 and removed after sunset
+var (
+	v1DeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	v1Sunset       = time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC)
+)
+
+// actorStorage returns storage which records revisions of background changes with actor
+func actorStorage(storage song.Storage, actor string) song.Storage {
+
+	if revisioner, ok := storage.(song.Revisioner); ok {
+		return revisioner.As(actor)
+	}
+	return storage
+}
+
+// infoProvider builds cached chain of info APIs from INFO_PROVIDERS or HTTP_EXTERNALAPI,
+// sources are returned for breaker states endpoint
+func infoProvider(cfg *config.Config) (info.MetadataProvider, []*info.Source, error) {
+
+	spec := cfg.InfoProviders
+	if spec == "" {
+		spec = cfg.ExternalAPI
+	}
+
+	sources, err := info.ParseSources(spec, info.SourceOptions{
+		Timeout:            cfg.ExternalAPITimeout,
+		ConnectTimeout:     cfg.ExternalAPIConnectTimeout,
+		ReadTimeout:        cfg.ExternalAPIReadTimeout,
+		BreakerFailures:    cfg.InfoBreakerFailures,
+		BreakerOpenTimeout: cfg.InfoBreakerOpenTimeout,
+		MaxConcurrent:      cfg.InfoMaxConcurrent,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return info.NewCache(&info.Chain{Sources: sources}, cfg.InfoCacheTTL), sources, nil
+}
+
+// setEnrichDefaults fills enrichment settings missing in config
+func setEnrichDefaults(cfg *config.Config) {
+	if cfg.ExternalAPITimeout == 0 {
+		cfg.ExternalAPITimeout = 10 * time.Second
+	}
+	if cfg.ExternalAPIConnectTimeout == 0 {
+		cfg.ExternalAPIConnectTimeout = 2 * time.Second
+	}
+	if cfg.ExternalAPIReadTimeout == 0 {
+		cfg.ExternalAPIReadTimeout = 5 * time.Second
+	}
+	if cfg.InfoCacheTTL == 0 {
+		cfg.InfoCacheTTL = 10 * time.Minute
+	}
+	if cfg.InfoBreakerFailures == 0 {
+		cfg.InfoBreakerFailures = 5
+	}
+	if cfg.InfoBreakerOpenTimeout == 0 {
+		cfg.InfoBreakerOpenTimeout = 30 * time.Second
+	}
+	if cfg.InfoMaxConcurrent == 0 {
+		cfg.InfoMaxConcurrent = 4
+	}
+	if cfg.EnrichPollInterval == 0 {
+		cfg.EnrichPollInterval = 2 * time.Second
+	}
+	if cfg.EnrichMaxAttempts == 0 {
+		cfg.EnrichMaxAttempts = 5
+	}
+	if cfg.EnrichBackoff == 0 {
+		cfg.EnrichBackoff = 2 * time.Second
+	}
+	if cfg.EnrichMaxBackoff == 0 {
+		cfg.EnrichMaxBackoff = 10 * time.Minute
+	}
+	if cfg.EnrichStaleAfter == 0 {
+		cfg.EnrichStaleAfter = 5 * time.Minute
+	}
+	if cfg.RefreshInterval == 0 {
+		cfg.RefreshInterval = time.Hour
+	}
+	if cfg.RefreshMaxAge == 0 {
+		cfg.RefreshMaxAge = 30 * 24 * time.Hour
+	}
+	if cfg.RefreshIncompleteMinAge == 0 {
+		cfg.RefreshIncompleteMinAge = 24 * time.Hour
+	}
+	if cfg.RefreshBatchSize == 0 {
+		cfg.RefreshBatchSize = 50
+	}
+	if cfg.RefreshRateLimit == 0 {
+		cfg.RefreshRateLimit = time.Second
+	}
+}
+
+// setTrashDefaults fills trash settings missing in config
+func setTrashDefaults(cfg *config.Config) {
+	if cfg.TrashRetentionDays == 0 {
+		cfg.TrashRetentionDays = 30
+	}
+	if cfg.TrashPurgeInterval == 0 {
+		cfg.TrashPurgeInterval = time.Hour
+	}
+}
+
+func swaggerHandler(w http.ResponseWriter, r *http.Request) {
+	httpSwagger.WrapHandler(w, r)
+}
+
+func openPostgres(cfg *config.Config) (*sql.DB, error) {
+
 	dsn := fmt.Sprintf(
 		"postgres://%s:%s@%s/%s?sslmode=disable",
 		cfg.DBUsername,
@@ -40,49 +354,59 @@ func main() {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		log.Println("open sql connect failed")
-		return
+		return nil, err
 	}
-	defer db.Close()
 
-	migration, err := migrate.New("file://migration", dsn)
+	err = migrateUp(db, "file://migration", dsn)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func openSQLite(cfg *config.Config) (*sql.DB, error) {
+
+	db, err := sql.Open("sqlite", cfg.DBPath)
+	if err != nil {
+		log.Println("open sqlite file failed")
+		return nil, err
+	}
+	//sqlite allows only one writer at a time
+	db.SetMaxOpenConns(1)
+
+	err = migrateUp(db, "file://migration/sqlite", "sqlite://"+cfg.DBPath)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateUp(db *sql.DB, source, dsn string) error {
+
+	migration, err := migrate.New(source, dsn)
 	if err != nil {
 		log.Println("create migration error:", err.Error())
-		return
+		return err
 	}
+	defer migration.Close()
 
 	err = migration.Up()
 	if err != nil && err != migrate.ErrNoChange {
 		log.Println("migratiom up error:", err.Error())
-		return
+		return err
 	}
 
 	err = db.Ping()
 	if err != nil {
 		log.Println("ping failed")
-		return
+		return err
 	}
 
 	log.Println("db successfully connected")
 
-	songHandler := &song.SongHandler{
-		Storage:     storage.NewStorage(db),
-		ExternalAPI: cfg.ExternalAPI,
-	}
-
-	mux := http.NewServeMux()
-
-	mux.HandleFunc("GET /swagger/", swaggerHandler)
-	mux.HandleFunc("GET /api/songs", songHandler.GetAll)
-	mux.HandleFunc("GET /api/songs/{id}", songHandler.Get)
-	mux.HandleFunc("PUT /api/songs", songHandler.New)
-	mux.HandleFunc("POST /api/songs", songHandler.Update)
-	mux.HandleFunc("DELETE /api/songs", songHandler.Delete)
-
-	log.Println("start server at", cfg.HTTPPort)
-	http.ListenAndServe(":"+cfg.HTTPPort, mux)
-
-}
-
-func swaggerHandler(w http.ResponseWriter, r *http.Request) {
-	httpSwagger.WrapHandler(w, r)
+	return nil
 }
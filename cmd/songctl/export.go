@@ -0,0 +1,111 @@
+package main
+
+import (
+	"SongLibrary/pkg/song"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// runExport writes answer of GET /api/songs/export to file as it is received
+func runExport(args []string) error {
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	server := flags.String("server", "http://127.0.0.1:8080", "SongLibrary API address")
+	format := flags.String("format", song.ExportJSON, "json, jsonl or csv")
+	output := flags.String("o", "-", "output file, \"-\" writes to stdout")
+
+	//filters are the same as query params of songs list
+	filters := map[string]*string{
+		"song":         flags.String("song", "", "part of song name"),
+		"group":        flags.String("group", "", "part of group name"),
+		"releaseDate":  flags.String("release-date", "", "release year"),
+		"releasedFrom": flags.String("released-from", "", "songs released on this day, month, year or decade like 1990s or later"),
+		"releasedTo":   flags.String("released-to", "", "songs released on this day, month, year or decade like 1990s or earlier"),
+		"text":         flags.String("text", "", "part of text"),
+		"link":         flags.String("link", "", "true or false, songs with or without link"),
+		"album":        flags.String("album", "", "album id"),
+		"tags":         flags.String("tags", "", "comma separated tag names"),
+		"tagsMode":     flags.String("tags-mode", "", "and or or"),
+		"q":            flags.String("q", "", "query like group:muse year:2006..2010 -link:false"),
+		"sort":         flags.String("sort", "", "comma separated fields with - for descending order, e.g. -releaseDate,group"),
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		return errors.New("unexpected arguments: " + strings.Join(flags.Args(), " "))
+	}
+
+	params := url.Values{}
+	params.Add("format", *format)
+	for name, value := range filters {
+		if *value != "" {
+			params.Add(name, *value)
+		}
+	}
+
+	resp, err := http.Get(strings.TrimSuffix(*server, "/") + "/api/songs/export?" + params.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		answer := &struct {
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(answer)
+		if err != nil || answer.Error == nil {
+			return fmt.Errorf("status [%d]", resp.StatusCode)
+		}
+		return fmt.Errorf("status [%d]: %s", resp.StatusCode, answer.Error.Message)
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	//server reports error after the start of answer only in trailer, it is read after the whole body
+	tail := &tailWriter{}
+	_, err = io.Copy(io.MultiWriter(out, tail), resp.Body)
+	if err != nil {
+		return fmt.Errorf("export is not complete: %w", err)
+	}
+	if message := resp.Trailer.Get(song.ExportErrorTrailer); message != "" {
+		return fmt.Errorf("export is not complete: %s", message)
+	}
+	if *format == song.ExportJSON && tail.last != ']' {
+		return errors.New("export is not complete: json array is not closed")
+	}
+
+	return nil
+}
+
+// tailWriter keeps the last written byte which is not space
+type tailWriter struct {
+	last byte
+}
+
+func (tw *tailWriter) Write(p []byte) (int, error) {
+
+	data := bytes.TrimRight(p, " \t\r\n")
+	if len(data) > 0 {
+		tw.last = data[len(data)-1]
+	}
+	return len(p), nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"SongLibrary/pkg/song"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+type importAnswer struct {
+	Response struct {
+		Created int                  `json:"created"`
+		Updated int                  `json:"updated"`
+		Skipped int                  `json:"skipped"`
+		Failed  int                  `json:"failed"`
+		Rows    []*song.ImportResult `json:"rows"`
+	} `json:"response"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// runImport sends file to POST /api/songs/import as a stream and prints result of every row
+func runImport(args []string) error {
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	server := flags.String("server", "http://127.0.0.1:8080", "SongLibrary API address")
+	format := flags.String("format", "", "csv or jsonl, taken from file extension if empty")
+	onDuplicate := flags.String("on-duplicate", song.DuplicateFail, "what to do with existing song of group: fail, skip or update")
+	quiet := flags.Bool("q", false, "print only failed rows and totals")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New("one file is expected, \"-\" reads stdin")
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = song.ImportCSV
+		case ".jsonl", ".ndjson":
+			*format = song.ImportJSONL
+		default:
+			return errors.New("format is unknown, use -format csv or -format jsonl")
+		}
+	}
+	if !song.IsImportFormat(*format) {
+		return errors.New("format must be csv or jsonl")
+	}
+	if !song.IsDuplicateMode(*onDuplicate) {
+		return errors.New("on-duplicate must be fail, skip or update")
+	}
+
+	var body io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		body = file
+	}
+
+	params := url.Values{}
+	params.Add("format", *format)
+	params.Add("onDuplicate", *onDuplicate)
+
+	resp, err := http.Post(strings.TrimSuffix(*server, "/")+"/api/songs/import?"+params.Encode(), "application/octet-stream", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	answer := &importAnswer{}
+	err = json.NewDecoder(resp.Body).Decode(answer)
+	if err != nil {
+		return fmt.Errorf("bad answer with status [%d]: %w", resp.StatusCode, err)
+	}
+	if answer.Error != nil {
+		return fmt.Errorf("status [%d]: %s", resp.StatusCode, answer.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status [%d]", resp.StatusCode)
+	}
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "LINE\tSTATUS\tID\tSONG\tGROUP\tERROR")
+	for _, row := range answer.Response.Rows {
+		if *quiet && row.Status != song.ImportFailed {
+			continue
+		}
+		id := ""
+		if row.ID != 0 {
+			id = fmt.Sprint(row.ID)
+		}
+		fmt.Fprintf(out, "%d\t%s\t%s\t%s\t%s\t%s\n", row.Line, row.Status, id, row.Song, row.Group, row.Error)
+	}
+	out.Flush()
+
+	fmt.Printf("created: %d, updated: %d, skipped: %d, failed: %d\n",
+		answer.Response.Created, answer.Response.Updated, answer.Response.Skipped, answer.Response.Failed)
+
+	if answer.Response.Failed > 0 {
+		return errors.New("some rows are not imported")
+	}
+	return nil
+}
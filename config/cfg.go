@@ -1,16 +1,45 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	HTTPPort    string `mapstructure:"HTTP_PORT"`
 	ExternalAPI string `mapstructure:"HTTP_EXTERNALAPI"`
+	DBDriver    string `mapstructure:"DB_DRIVER"`
+	DBPath      string `mapstructure:"DB_PATH"`
 	DBHost      string `mapstructure:"DB_HOST"`
 	DBName      string `mapstructure:"DB_NAME"`
 	DBUsername  string `mapstructure:"DB_USERNAME"`
 	DBPassword  string `mapstructure:"DB_PASSWORD"`
+
+	FuzzyThreshold  float64 `mapstructure:"FUZZY_THRESHOLD"`
+	ImportBatchSize int     `mapstructure:"IMPORT_BATCH_SIZE"`
+	RequireIfMatch  bool    `mapstructure:"SONGS_REQUIRE_IF_MATCH"`
+
+	ExternalAPITimeout        time.Duration `mapstructure:"HTTP_EXTERNALAPI_TIMEOUT"`
+	ExternalAPIConnectTimeout time.Duration `mapstructure:"HTTP_EXTERNALAPI_CONNECT_TIMEOUT"`
+	ExternalAPIReadTimeout    time.Duration `mapstructure:"HTTP_EXTERNALAPI_READ_TIMEOUT"`
+	InfoProviders             string        `mapstructure:"INFO_PROVIDERS"`
+	InfoCacheTTL              time.Duration `mapstructure:"INFO_CACHE_TTL"`
+	InfoBreakerFailures       int           `mapstructure:"INFO_BREAKER_FAILURES"`
+	InfoBreakerOpenTimeout    time.Duration `mapstructure:"INFO_BREAKER_OPEN_TIMEOUT"`
+	InfoMaxConcurrent         int           `mapstructure:"INFO_MAX_CONCURRENT"`
+	EnrichPollInterval        time.Duration `mapstructure:"ENRICH_POLL_INTERVAL"`
+	EnrichMaxAttempts         int           `mapstructure:"ENRICH_MAX_ATTEMPTS"`
+	EnrichBackoff             time.Duration `mapstructure:"ENRICH_BACKOFF"`
+	EnrichMaxBackoff          time.Duration `mapstructure:"ENRICH_MAX_BACKOFF"`
+	EnrichStaleAfter          time.Duration `mapstructure:"ENRICH_STALE_AFTER"`
+	RefreshInterval           time.Duration `mapstructure:"REFRESH_INTERVAL"`
+	RefreshMaxAge             time.Duration `mapstructure:"REFRESH_MAX_AGE"`
+	RefreshIncompleteMinAge   time.Duration `mapstructure:"REFRESH_INCOMPLETE_MIN_AGE"`
+	RefreshBatchSize          int           `mapstructure:"REFRESH_BATCH_SIZE"`
+	RefreshRateLimit          time.Duration `mapstructure:"REFRESH_RATE_LIMIT"`
+	TrashRetentionDays        int           `mapstructure:"TRASH_RETENTION_DAYS"`
+	TrashPurgeInterval        time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
 }
 
 func ReadConfig(name, path string) (*Config, error) {
@@ -18,6 +47,8 @@ func ReadConfig(name, path string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigName(name)
 	v.AddConfigPath(path)
+	//environment variables override values of config file
+	v.AutomaticEnv()
 	err := v.ReadInConfig()
 	if err != nil {
 		return nil, err
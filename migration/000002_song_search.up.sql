@@ -0,0 +1,20 @@
+ALTER TABLE songs ADD COLUMN "search_vector" tsvector;
+
+-- names are not stemmed, lyrics are stemmed for every supported search language
+CREATE FUNCTION songs_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', coalesce(NEW.song_name, '') || ' ' || coalesce(NEW.group_name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.text, '')), 'B') ||
+        setweight(to_tsvector('russian', coalesce(NEW.text, '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF song_name, group_name, text ON songs
+    FOR EACH ROW EXECUTE FUNCTION songs_search_vector_update();
+
+UPDATE songs SET text = text;
+
+CREATE INDEX songs_search_vector_idx ON songs USING gin ("search_vector");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS songs_group_name_trgm_idx;
+DROP INDEX IF EXISTS songs_song_name_trgm_idx;
@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX songs_song_name_trgm_idx ON songs USING gin ("song_name" gin_trgm_ops);
+CREATE INDEX songs_group_name_trgm_idx ON songs USING gin ("group_name" gin_trgm_ops);
@@ -0,0 +1,29 @@
+DROP TRIGGER IF EXISTS groups_search_vector_trigger ON groups;
+DROP FUNCTION IF EXISTS groups_search_vector_update();
+DROP TRIGGER IF EXISTS songs_search_vector_trigger ON songs;
+
+ALTER TABLE songs ADD COLUMN "group_name" varchar(100);
+UPDATE songs SET group_name = groups.name FROM groups WHERE songs.group_id = groups.id;
+ALTER TABLE songs ALTER COLUMN "group_name" SET NOT NULL;
+
+DROP INDEX IF EXISTS songs_group_id_idx;
+ALTER TABLE songs DROP CONSTRAINT IF EXISTS songs_song_name_group_id_key;
+ALTER TABLE songs DROP COLUMN "group_id";
+ALTER TABLE songs ADD CONSTRAINT songs_song_name_group_name_key UNIQUE ("song_name", "group_name");
+CREATE INDEX songs_group_name_trgm_idx ON songs USING gin ("group_name" gin_trgm_ops);
+
+CREATE OR REPLACE FUNCTION songs_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', coalesce(NEW.song_name, '') || ' ' || coalesce(NEW.group_name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.text, '')), 'B') ||
+        setweight(to_tsvector('russian', coalesce(NEW.text, '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF song_name, group_name, text ON songs
+    FOR EACH ROW EXECUTE FUNCTION songs_search_vector_update();
+
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,52 @@
+CREATE TABLE groups (
+    "id" serial PRIMARY KEY,
+    "name" varchar(100) NOT NULL
+);
+
+-- "Muse" and "muse" are the same group
+CREATE UNIQUE INDEX groups_name_key ON groups (lower("name"));
+CREATE INDEX groups_name_trgm_idx ON groups USING gin ("name" gin_trgm_ops);
+
+-- the first spelling in alphabetical order is kept for groups that differ only in case
+INSERT INTO groups ("name")
+SELECT DISTINCT ON (lower(group_name)) group_name FROM songs ORDER BY lower(group_name), group_name;
+
+ALTER TABLE songs ADD COLUMN "group_id" integer REFERENCES groups ("id") ON DELETE RESTRICT;
+UPDATE songs SET group_id = groups.id FROM groups WHERE lower(songs.group_name) = lower(groups.name);
+ALTER TABLE songs ALTER COLUMN "group_id" SET NOT NULL;
+
+DROP TRIGGER songs_search_vector_trigger ON songs;
+DROP INDEX songs_group_name_trgm_idx;
+ALTER TABLE songs DROP CONSTRAINT songs_song_name_group_name_key;
+ALTER TABLE songs DROP COLUMN "group_name";
+
+-- fails if the same song was stored for groups that differ only in case, such duplicates must be removed first
+ALTER TABLE songs ADD CONSTRAINT songs_song_name_group_id_key UNIQUE ("song_name", "group_id");
+CREATE INDEX songs_group_id_idx ON songs ("group_id");
+
+CREATE OR REPLACE FUNCTION songs_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', coalesce(NEW.song_name, '') || ' ' || coalesce((SELECT name FROM groups WHERE id = NEW.group_id), '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.text, '')), 'B') ||
+        setweight(to_tsvector('russian', coalesce(NEW.text, '')), 'B');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER songs_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF song_name, group_id, text ON songs
+    FOR EACH ROW EXECUTE FUNCTION songs_search_vector_update();
+
+-- renamed group must be found by its new name
+CREATE FUNCTION groups_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    UPDATE songs SET group_id = group_id WHERE group_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER groups_search_vector_trigger
+    AFTER UPDATE OF name ON groups
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION groups_search_vector_update();
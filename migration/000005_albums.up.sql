@@ -0,0 +1,22 @@
+CREATE TABLE albums (
+    "id" serial PRIMARY KEY,
+    "title" varchar(100) NOT NULL,
+    "group_id" integer NOT NULL REFERENCES groups ("id") ON DELETE RESTRICT,
+    "release_date" date,
+    "cover_link" varchar(255),
+    UNIQUE ("title", "group_id")
+);
+
+CREATE INDEX albums_group_id_idx ON albums ("group_id");
+
+-- position constraint is deferrable, so tracks can be swapped inside one transaction
+CREATE TABLE album_songs (
+    "album_id" integer NOT NULL REFERENCES albums ("id") ON DELETE CASCADE,
+    "song_id" integer NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "disc_number" integer NOT NULL DEFAULT 1 CHECK ("disc_number" > 0),
+    "track_number" integer NOT NULL CHECK ("track_number" > 0),
+    PRIMARY KEY ("album_id", "song_id"),
+    CONSTRAINT album_songs_position_key UNIQUE ("album_id", "disc_number", "track_number") DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE INDEX album_songs_song_id_idx ON album_songs ("song_id");
@@ -0,0 +1,16 @@
+-- genres are tags of kind genre, so both share one namespace and one filter
+CREATE TABLE tags (
+    "id" serial PRIMARY KEY,
+    "name" varchar(50) NOT NULL,
+    "kind" varchar(10) NOT NULL DEFAULT 'tag' CHECK ("kind" IN ('tag', 'genre'))
+);
+
+CREATE UNIQUE INDEX tags_name_key ON tags (lower("name"));
+
+CREATE TABLE song_tags (
+    "song_id" integer NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "tag_id" integer NOT NULL REFERENCES tags ("id") ON DELETE CASCADE,
+    PRIMARY KEY ("song_id", "tag_id")
+);
+
+CREATE INDEX song_tags_tag_id_idx ON song_tags ("tag_id");
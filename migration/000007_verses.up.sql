@@ -0,0 +1,32 @@
+CREATE TABLE verses (
+    "song_id" integer NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "position" integer NOT NULL CHECK ("position" > 0),
+    "kind" varchar(10) NOT NULL DEFAULT 'verse' CHECK ("kind" IN ('verse', 'chorus', 'bridge', 'intro')),
+    "text" text NOT NULL,
+    PRIMARY KEY ("song_id", "position")
+);
+
+-- existing texts are parsed the same way as new ones: blocks are split by empty lines,
+-- trailing spaces are removed and label like [Chorus] on the first line sets kind
+WITH blocks AS (
+    SELECT s.id AS song_id, b.ord,
+        btrim(regexp_replace(b.block, '[ \t]+(\n|$)', '\1', 'g'), E'\n') AS block
+    FROM songs s,
+    regexp_split_to_table(regexp_replace(s.text, '\r\n?', E'\n', 'g'), '\n[ \t]*\n') WITH ORDINALITY AS b(block, ord)
+    WHERE s.text IS NOT NULL
+), labeled AS (
+    SELECT song_id, ord,
+        lower(btrim(substring(block FROM '^\s*\[([^]\n]*)\]\s*(\n|$)'))) AS label,
+        btrim(regexp_replace(block, '^\s*\[[^]\n]*\]\s*(\n|$)', ''), E'\n') AS body
+    FROM blocks
+)
+INSERT INTO verses ("song_id", "position", "kind", "text")
+SELECT song_id, row_number() OVER (PARTITION BY song_id ORDER BY ord),
+    CASE
+        WHEN label ~ '^(chorus|refrain|hook)' THEN 'chorus'
+        WHEN label ~ '^bridge' THEN 'bridge'
+        WHEN label ~ '^intro' THEN 'intro'
+        ELSE 'verse'
+    END,
+    body
+FROM labeled WHERE body <> '';
@@ -0,0 +1,12 @@
+-- one job per song, retrigger resets it to pending
+CREATE TABLE enrichment_jobs (
+    "song_id" integer PRIMARY KEY REFERENCES songs ("id") ON DELETE CASCADE,
+    "status" varchar(10) NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'running', 'done', 'failed')),
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text,
+    "next_run_at" timestamptz NOT NULL DEFAULT now(),
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    "updated_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX enrichment_jobs_ready_idx ON enrichment_jobs ("next_run_at") WHERE "status" IN ('pending', 'running');
@@ -0,0 +1,28 @@
+ALTER TABLE songs ADD COLUMN "last_enriched_at" timestamptz;
+
+UPDATE songs SET last_enriched_at = enrichment_jobs.updated_at
+FROM enrichment_jobs
+WHERE enrichment_jobs.song_id = songs.id AND enrichment_jobs.status = 'done';
+
+CREATE INDEX songs_last_enriched_at_idx ON songs ("last_enriched_at" NULLS FIRST);
+
+CREATE TABLE refresh_runs (
+    "id" serial PRIMARY KEY,
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz NOT NULL,
+    "checked" integer NOT NULL,
+    "updated" integer NOT NULL,
+    "failed" integer NOT NULL
+);
+
+-- song_id is not a foreign key, so report is kept after song is deleted
+CREATE TABLE refresh_changes (
+    "id" serial PRIMARY KEY,
+    "run_id" integer NOT NULL REFERENCES refresh_runs ("id") ON DELETE CASCADE,
+    "song_id" integer NOT NULL,
+    "field" varchar(20) NOT NULL,
+    "old_value" text NOT NULL,
+    "new_value" text NOT NULL
+);
+
+CREATE INDEX refresh_changes_run_id_idx ON refresh_changes ("run_id");
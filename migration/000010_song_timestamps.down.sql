@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS songs_updated_at_trigger ON songs;
+DROP FUNCTION IF EXISTS songs_updated_at_update();
+DROP INDEX IF EXISTS songs_updated_at_idx;
+DROP INDEX IF EXISTS songs_created_at_idx;
+DROP INDEX IF EXISTS songs_release_date_idx;
+ALTER TABLE songs DROP COLUMN IF EXISTS "updated_at";
+ALTER TABLE songs DROP COLUMN IF EXISTS "created_at";
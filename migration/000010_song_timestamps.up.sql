@@ -0,0 +1,20 @@
+ALTER TABLE songs ADD COLUMN "created_at" timestamptz NOT NULL DEFAULT now();
+ALTER TABLE songs ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT now();
+
+-- id is the last key of every order, so keyset pagination can use these indexes
+CREATE INDEX songs_release_date_idx ON songs ("release_date", "id");
+CREATE INDEX songs_created_at_idx ON songs ("created_at", "id");
+CREATE INDEX songs_updated_at_idx ON songs ("updated_at", "id");
+
+CREATE FUNCTION songs_updated_at_update() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := now();
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+-- refresh of search vector and enrichment marks do not change song
+CREATE TRIGGER songs_updated_at_trigger
+    BEFORE UPDATE ON songs
+    FOR EACH ROW WHEN ((OLD.song_name, OLD.group_id, OLD.release_date, OLD.text, OLD.link) IS DISTINCT FROM (NEW.song_name, NEW.group_id, NEW.release_date, NEW.text, NEW.link))
+    EXECUTE FUNCTION songs_updated_at_update();
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS songs_updated_at_trigger ON songs;
+
+CREATE TRIGGER songs_updated_at_trigger
+    BEFORE UPDATE ON songs
+    FOR EACH ROW WHEN ((OLD.song_name, OLD.group_id, OLD.release_date, OLD.text, OLD.link) IS DISTINCT FROM (NEW.song_name, NEW.group_id, NEW.release_date, NEW.text, NEW.link))
+    EXECUTE FUNCTION songs_updated_at_update();
+
+ALTER TABLE songs DROP COLUMN IF EXISTS "release_date_precision";
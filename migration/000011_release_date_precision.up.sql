@@ -0,0 +1,10 @@
+-- date known to month or year is stored as the first day of that period
+ALTER TABLE songs ADD COLUMN "release_date_precision" varchar(5) NOT NULL DEFAULT 'day'
+    CHECK ("release_date_precision" IN ('day', 'month', 'year'));
+
+DROP TRIGGER songs_updated_at_trigger ON songs;
+
+CREATE TRIGGER songs_updated_at_trigger
+    BEFORE UPDATE ON songs
+    FOR EACH ROW WHEN ((OLD.song_name, OLD.group_id, OLD.release_date, OLD.release_date_precision, OLD.text, OLD.link) IS DISTINCT FROM (NEW.song_name, NEW.group_id, NEW.release_date, NEW.release_date_precision, NEW.text, NEW.link))
+    EXECUTE FUNCTION songs_updated_at_update();
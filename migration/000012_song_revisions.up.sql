@@ -0,0 +1,13 @@
+-- song_id is not a foreign key, so revisions are kept after song is deleted,
+-- states are song fields before and after revision, NULL when song did not exist
+CREATE TABLE song_revisions (
+    "id" serial PRIMARY KEY,
+    "song_id" integer NOT NULL,
+    "action" varchar(10) NOT NULL CHECK ("action" IN ('create', 'update', 'delete', 'restore')),
+    "actor" varchar(100) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    "old_state" jsonb,
+    "new_state" jsonb
+);
+
+CREATE INDEX song_revisions_song_id_idx ON song_revisions ("song_id", "id");
@@ -0,0 +1,6 @@
+DELETE FROM songs WHERE "deleted_at" IS NOT NULL;
+
+DROP INDEX IF EXISTS songs_deleted_at_idx;
+DROP INDEX IF EXISTS songs_song_name_group_id_key;
+ALTER TABLE songs ADD CONSTRAINT songs_song_name_group_id_key UNIQUE ("song_name", "group_id");
+ALTER TABLE songs DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,6 @@
+-- deleted song is kept in trash until purge, names of trashed songs can be used by new songs
+ALTER TABLE songs ADD COLUMN "deleted_at" timestamptz;
+
+ALTER TABLE songs DROP CONSTRAINT songs_song_name_group_id_key;
+CREATE UNIQUE INDEX songs_song_name_group_id_key ON songs ("song_name", "group_id") WHERE "deleted_at" IS NULL;
+CREATE INDEX songs_deleted_at_idx ON songs ("deleted_at") WHERE "deleted_at" IS NOT NULL;
@@ -0,0 +1,8 @@
+CREATE OR REPLACE FUNCTION songs_updated_at_update() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := now();
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE songs DROP COLUMN "version";
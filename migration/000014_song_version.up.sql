@@ -0,0 +1,10 @@
+-- version grows with every change of song, it is sent as ETag and checked by If-Match
+ALTER TABLE songs ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION songs_updated_at_update() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := now();
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,6 @@
+CREATE OR REPLACE FUNCTION groups_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    UPDATE songs SET group_id = group_id WHERE group_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
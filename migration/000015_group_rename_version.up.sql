@@ -0,0 +1,8 @@
+-- song of renamed group is sent with new group name, so its version and ETag change too,
+-- songs_updated_at_trigger does not fire because group_id stays the same
+CREATE OR REPLACE FUNCTION groups_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    UPDATE songs SET group_id = group_id, version = version + 1, updated_at = now() WHERE group_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
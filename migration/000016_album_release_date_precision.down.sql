@@ -0,0 +1 @@
+ALTER TABLE albums DROP COLUMN IF EXISTS "release_date_precision";
@@ -0,0 +1,3 @@
+-- album release date known to month or year is stored as the first day of that period like release date of song
+ALTER TABLE albums ADD COLUMN "release_date_precision" varchar(5) NOT NULL DEFAULT 'day'
+    CHECK ("release_date_precision" IN ('day', 'month', 'year'));
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "songs";
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE songs (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "song_name" varchar(100) NOT NULL,
+    "group_name" varchar(100) NOT NULL,
+    "release_date" text,
+    "text" text,
+    "link" varchar(255),
+    UNIQUE ("song_name", "group_name")
+);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS songs_delete_verses;
+DROP TABLE IF EXISTS verses;
@@ -0,0 +1,27 @@
+CREATE TABLE verses (
+    "song_id" integer NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "position" integer NOT NULL CHECK ("position" > 0),
+    "kind" varchar(10) NOT NULL DEFAULT 'verse' CHECK ("kind" IN ('verse', 'chorus', 'bridge', 'intro')),
+    "text" text NOT NULL,
+    PRIMARY KEY ("song_id", "position")
+);
+
+-- foreign keys are not enforced by default in sqlite
+CREATE TRIGGER songs_delete_verses AFTER DELETE ON songs
+BEGIN
+    DELETE FROM verses WHERE song_id = old.id;
+END;
+
+-- existing texts are split by empty lines, labels are recognized only for texts written after this migration
+WITH RECURSIVE split(song_id, position, block, rest) AS (
+    SELECT id, 0, '', replace(replace(text, char(13) || char(10), char(10)), char(13), char(10)) || char(10) || char(10)
+    FROM songs WHERE text IS NOT NULL
+    UNION ALL
+    SELECT song_id, position + 1,
+        substr(rest, 1, instr(rest, char(10) || char(10)) - 1),
+        substr(rest, instr(rest, char(10) || char(10)) + 2)
+    FROM split WHERE rest <> ''
+)
+INSERT INTO verses ("song_id", "position", "kind", "text")
+SELECT song_id, row_number() OVER (PARTITION BY song_id ORDER BY position), 'verse', trim(block, char(10))
+FROM split WHERE trim(block, char(10) || ' ') <> '';
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS songs_delete_enrichment_jobs;
+DROP TABLE IF EXISTS enrichment_jobs;
@@ -0,0 +1,15 @@
+-- times are stored as UTC text "2006-01-02 15:04:05.000", so they can be compared as strings
+CREATE TABLE enrichment_jobs (
+    "song_id" integer PRIMARY KEY REFERENCES songs ("id") ON DELETE CASCADE,
+    "status" varchar(10) NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'running', 'done', 'failed')),
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text,
+    "next_run_at" text NOT NULL,
+    "created_at" text NOT NULL,
+    "updated_at" text NOT NULL
+);
+
+CREATE TRIGGER songs_delete_enrichment_jobs AFTER DELETE ON songs
+BEGIN
+    DELETE FROM enrichment_jobs WHERE song_id = old.id;
+END;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS refresh_changes;
+DROP TABLE IF EXISTS refresh_runs;
+ALTER TABLE songs DROP COLUMN "last_enriched_at";
@@ -0,0 +1,25 @@
+ALTER TABLE songs ADD COLUMN "last_enriched_at" text;
+
+UPDATE songs SET last_enriched_at = (
+    SELECT updated_at FROM enrichment_jobs WHERE enrichment_jobs.song_id = songs.id AND enrichment_jobs.status = 'done'
+);
+
+CREATE TABLE refresh_runs (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "started_at" text NOT NULL,
+    "finished_at" text NOT NULL,
+    "checked" integer NOT NULL,
+    "updated" integer NOT NULL,
+    "failed" integer NOT NULL
+);
+
+CREATE TABLE refresh_changes (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "run_id" integer NOT NULL REFERENCES refresh_runs ("id") ON DELETE CASCADE,
+    "song_id" integer NOT NULL,
+    "field" varchar(20) NOT NULL,
+    "old_value" text NOT NULL,
+    "new_value" text NOT NULL
+);
+
+CREATE INDEX refresh_changes_run_id_idx ON refresh_changes ("run_id");
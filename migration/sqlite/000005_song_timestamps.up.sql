@@ -0,0 +1,23 @@
+-- sqlite does not allow adding column with default now, so times are set by triggers
+ALTER TABLE songs ADD COLUMN "created_at" text;
+ALTER TABLE songs ADD COLUMN "updated_at" text;
+
+UPDATE songs SET created_at = strftime('%Y-%m-%d %H:%M:%f', 'now'), updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now');
+
+CREATE INDEX songs_release_date_idx ON songs ("release_date", "id");
+CREATE INDEX songs_created_at_idx ON songs ("created_at", "id");
+CREATE INDEX songs_updated_at_idx ON songs ("updated_at", "id");
+
+CREATE TRIGGER songs_created_at_trigger
+    AFTER INSERT ON songs
+    FOR EACH ROW WHEN NEW.created_at IS NULL
+BEGIN
+    UPDATE songs SET created_at = strftime('%Y-%m-%d %H:%M:%f', 'now'), updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER songs_updated_at_trigger
+    AFTER UPDATE OF song_name, group_name, release_date, text, link ON songs
+    FOR EACH ROW WHEN (OLD.song_name, OLD.group_name, OLD.release_date, OLD.text, OLD.link) IS NOT (NEW.song_name, NEW.group_name, NEW.release_date, NEW.text, NEW.link)
+BEGIN
+    UPDATE songs SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = NEW.id;
+END;
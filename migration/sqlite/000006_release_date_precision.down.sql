@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS songs_updated_at_trigger;
+
+CREATE TRIGGER songs_updated_at_trigger
+    AFTER UPDATE OF song_name, group_name, release_date, text, link ON songs
+    FOR EACH ROW WHEN (OLD.song_name, OLD.group_name, OLD.release_date, OLD.text, OLD.link) IS NOT (NEW.song_name, NEW.group_name, NEW.release_date, NEW.text, NEW.link)
+BEGIN
+    UPDATE songs SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = NEW.id;
+END;
+
+ALTER TABLE songs DROP COLUMN "release_date_precision";
@@ -0,0 +1,12 @@
+-- date known to month or year is stored as the first day of that period
+ALTER TABLE songs ADD COLUMN "release_date_precision" text NOT NULL DEFAULT 'day'
+    CHECK ("release_date_precision" IN ('day', 'month', 'year'));
+
+DROP TRIGGER songs_updated_at_trigger;
+
+CREATE TRIGGER songs_updated_at_trigger
+    AFTER UPDATE OF song_name, group_name, release_date, release_date_precision, text, link ON songs
+    FOR EACH ROW WHEN (OLD.song_name, OLD.group_name, OLD.release_date, OLD.release_date_precision, OLD.text, OLD.link) IS NOT (NEW.song_name, NEW.group_name, NEW.release_date, NEW.release_date_precision, NEW.text, NEW.link)
+BEGIN
+    UPDATE songs SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = NEW.id;
+END;
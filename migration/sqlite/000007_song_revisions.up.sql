@@ -0,0 +1,13 @@
+-- song_id is not a foreign key, so revisions are kept after song is deleted,
+-- states are JSON of song fields before and after revision, NULL when song did not exist
+CREATE TABLE song_revisions (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "song_id" integer NOT NULL,
+    "action" text NOT NULL CHECK ("action" IN ('create', 'update', 'delete', 'restore')),
+    "actor" text NOT NULL,
+    "created_at" text NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    "old_state" text,
+    "new_state" text
+);
+
+CREATE INDEX song_revisions_song_id_idx ON song_revisions ("song_id", "id");
@@ -0,0 +1,54 @@
+DELETE FROM songs WHERE "deleted_at" IS NOT NULL;
+
+CREATE TABLE songs_new (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "song_name" varchar(100) NOT NULL,
+    "group_name" varchar(100) NOT NULL,
+    "release_date" text,
+    "text" text,
+    "link" varchar(255),
+    "last_enriched_at" text,
+    "created_at" text,
+    "updated_at" text,
+    "release_date_precision" text NOT NULL DEFAULT 'day'
+        CHECK ("release_date_precision" IN ('day', 'month', 'year')),
+    UNIQUE ("song_name", "group_name")
+);
+
+INSERT INTO songs_new ("id", "song_name", "group_name", "release_date", "text", "link", "last_enriched_at", "created_at", "updated_at", "release_date_precision")
+SELECT "id", "song_name", "group_name", "release_date", "text", "link", "last_enriched_at", "created_at", "updated_at", "release_date_precision" FROM songs;
+
+-- keep sequence, so ids of purged songs are not reused by songs_new
+DELETE FROM sqlite_sequence WHERE name = 'songs_new';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'songs_new', seq FROM sqlite_sequence WHERE name = 'songs';
+
+DROP TABLE songs;
+ALTER TABLE songs_new RENAME TO songs;
+
+CREATE INDEX songs_release_date_idx ON songs ("release_date", "id");
+CREATE INDEX songs_created_at_idx ON songs ("created_at", "id");
+CREATE INDEX songs_updated_at_idx ON songs ("updated_at", "id");
+
+CREATE TRIGGER songs_delete_verses AFTER DELETE ON songs
+BEGIN
+    DELETE FROM verses WHERE song_id = old.id;
+END;
+
+CREATE TRIGGER songs_delete_enrichment_jobs AFTER DELETE ON songs
+BEGIN
+    DELETE FROM enrichment_jobs WHERE song_id = old.id;
+END;
+
+CREATE TRIGGER songs_created_at_trigger
+    AFTER INSERT ON songs
+    FOR EACH ROW WHEN NEW.created_at IS NULL
+BEGIN
+    UPDATE songs SET created_at = strftime('%Y-%m-%d %H:%M:%f', 'now'), updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER songs_updated_at_trigger
+    AFTER UPDATE OF song_name, group_name, release_date, release_date_precision, text, link ON songs
+    FOR EACH ROW WHEN (OLD.song_name, OLD.group_name, OLD.release_date, OLD.release_date_precision, OLD.text, OLD.link) IS NOT (NEW.song_name, NEW.group_name, NEW.release_date, NEW.release_date_precision, NEW.text, NEW.link)
+BEGIN
+    UPDATE songs SET updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = NEW.id;
+END;
@@ -0,0 +1,3 @@
+DROP INDEX songs_group_idx;
+DROP INDEX songs_song_name_group_key;
+CREATE UNIQUE INDEX songs_song_name_group_name_key ON songs ("song_name", "group_name") WHERE "deleted_at" IS NULL;
@@ -0,0 +1,8 @@
+-- groups which differ only in case are the same group like in postgres, the first spelling is kept.
+-- fails if the same song was stored for groups that differ only in case, such duplicates must be removed first
+UPDATE songs SET group_name = (SELECT min(s.group_name) FROM songs AS s WHERE lower(s.group_name) = lower(songs.group_name))
+WHERE group_name <> (SELECT min(s.group_name) FROM songs AS s WHERE lower(s.group_name) = lower(songs.group_name));
+
+DROP INDEX songs_song_name_group_name_key;
+CREATE UNIQUE INDEX songs_song_name_group_key ON songs ("song_name", lower("group_name")) WHERE "deleted_at" IS NULL;
+CREATE INDEX songs_group_idx ON songs (lower("group_name"));
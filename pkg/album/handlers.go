@@ -0,0 +1,277 @@
+package album
+
+import (
+	"SongLibrary/pkg/song"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// @Summary Get albums list
+// @Description Get albums list with pagination and filtering by group name, without tracklists
+// @Tags albums
+// @ID get-albums
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Param group query string false "group name fragment, case insensitive"
+// @Produce json
+// @Success 200 {object} song.Response{response=[]album.Album}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums [get]
+func (ah *AlbumHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	limit, offset, err := song.ReadPagination(r)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	albums, err := ah.Storage.GetAlbums(limit, offset, r.FormValue("group"))
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": albums,
+	})
+}
+
+// @Summary Get album
+// @Description Get album with tracklist ordered by disc and track numbers
+// @Tags albums
+// @ID get-album
+// @Param id path int true "album id"
+// @Produce json
+// @Success 200 {object} song.Response{response=album.Album}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums/{id} [get]
+func (ah *AlbumHandler) Get(w http.ResponseWriter, r *http.Request) {
+
+	id, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	album, err := ah.Storage.GetAlbum(id)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": album,
+	})
+}
+
+// @Summary Add new album
+// @Description Add new album, group is created if it does not exist, release date has format day.month.year, month.year or year
+// @Tags albums
+// @ID new-album
+// @Param bodyJSON body string true "album title and group name are required" SchemaExample({"title":"black holes and revelations","group":"muse","releaseDate":"03.07.2006","coverLink":"some link"})
+// @Accept json
+// @Produce json
+// @Success 201 {object} song.Response{response=song.Response{id=int}}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums [put]
+func (ah *AlbumHandler) New(w http.ResponseWriter, r *http.Request) {
+
+	album := &Album{}
+	err := song.ReadJSON(r, album)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	album.Title = strings.TrimSpace(album.Title)
+	album.Group = strings.TrimSpace(album.Group)
+	if album.Title == "" || album.Group == "" {
+		song.WriteError(w, r, song.NewValidationError("", "title and group values must be not empty"))
+		return
+	}
+
+	album.ID, err = ah.Storage.AddAlbum(album)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusCreated, song.Response{
+		"response": song.Response{
+			"id": album.ID,
+		},
+	})
+}
+
+// @Summary Delete album
+// @Description Delete album, its songs stay in library
+// @Tags albums
+// @ID delete-album
+// @Param bodyJSON body string true "album id" SchemaExample({"id":2})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums [delete]
+func (ah *AlbumHandler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	album := &Album{}
+	err := song.ReadJSON(r, album)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	err = ah.Storage.DeleteAlbum(album.ID)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("album deleted, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", album.ID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+// @Summary Attach song to album
+// @Description Attach song to album, disc 1 is used by default and track number is the next one on the disc if not set
+// @Tags albums
+// @ID attach-album-song
+// @Param id path int true "album id"
+// @Param bodyJSON body string true "song id is required" SchemaExample({"songId":3,"disc":1,"track":2})
+// @Accept json
+// @Produce json
+// @Success 201 {object} song.Response{response=album.Track}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums/{id}/songs [put]
+func (ah *AlbumHandler) AttachSong(w http.ResponseWriter, r *http.Request) {
+
+	albumID, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	track := &Track{}
+	err = song.ReadJSON(r, track)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	if track.Disc < 0 || track.Number < 0 {
+		song.WriteError(w, r, song.NewValidationError("", "disc and track numbers cannot be negative"))
+		return
+	}
+
+	err = ah.Storage.AttachSong(albumID, track)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusCreated, song.Response{
+		"response": track,
+	})
+}
+
+// @Summary Detach song from album
+// @Description Detach song from album, positions of other tracks are not changed
+// @Tags albums
+// @ID detach-album-song
+// @Param id path int true "album id"
+// @Param bodyJSON body string true "song id" SchemaExample({"songId":3})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums/{id}/songs [delete]
+func (ah *AlbumHandler) DetachSong(w http.ResponseWriter, r *http.Request) {
+
+	albumID, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	track := &Track{}
+	err = song.ReadJSON(r, track)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	err = ah.Storage.DetachSong(albumID, track.SongID)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("song detached from album, album id: [%d], song id: [%d], user agent: [%s], path: [%s], method: [%s]\n", albumID, track.SongID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+// @Summary Reorder album tracks
+// @Description Set new disc and track numbers for listed songs of album in one transaction, so tracks can be swapped
+// @Tags albums
+// @ID reorder-album-songs
+// @Param id path int true "album id"
+// @Param bodyJSON body string true "new positions of album songs" SchemaExample({"tracks":[{"songId":3,"disc":1,"track":1},{"songId":1,"disc":1,"track":2}]})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/albums/{id}/songs [post]
+func (ah *AlbumHandler) ReorderTracks(w http.ResponseWriter, r *http.Request) {
+
+	albumID, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	body := &struct {
+		Tracks []*Track `json:"tracks"`
+	}{}
+	err = song.ReadJSON(r, body)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	if len(body.Tracks) == 0 {
+		song.WriteError(w, r, song.NewValidationError("tracks", "must be not empty"))
+		return
+	}
+
+	for _, track := range body.Tracks {
+		if track.Disc < 0 || track.Number <= 0 {
+			song.WriteError(w, r, song.NewValidationError("tracks", "track numbers must be positive and disc cannot be negative"))
+			return
+		}
+		if track.Disc == 0 {
+			track.Disc = 1
+		}
+	}
+
+	err = ah.Storage.ReorderTracks(albumID, body.Tracks)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("album tracks reordered, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", albumID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
@@ -0,0 +1,36 @@
+package album
+
+// Album model info
+// @Description album information, tracks are ordered by disc and track numbers
+type Album struct {
+	ID          int      `json:"id"`
+	Title       string   `json:"title"`
+	Group       string   `json:"group"`
+	ReleaseDate string   `json:"releaseDate"`
+	CoverLink   string   `json:"coverLink"`
+	Tracks      []*Track `json:"tracks,omitempty"`
+}
+
+// Track model info
+// @Description song position on album
+type Track struct {
+	SongID int    `json:"songId"`
+	Song   string `json:"song,omitempty"`
+	Group  string `json:"group,omitempty"`
+	Disc   int    `json:"disc"`
+	Number int    `json:"track"`
+}
+
+type AlbumHandler struct {
+	Storage
+}
+
+type Storage interface {
+	AddAlbum(album *Album) (int, error)
+	DeleteAlbum(id int) error
+	GetAlbum(id int) (*Album, error)
+	GetAlbums(limit, offset int, groupFragment string) ([]*Album, error)
+	AttachSong(albumID int, track *Track) error
+	DetachSong(albumID, songID int) error
+	ReorderTracks(albumID int, tracks []*Track) error
+}
@@ -0,0 +1,40 @@
+package enrich
+
+import (
+	"SongLibrary/pkg/song"
+	"net/http"
+)
+
+type RefreshHandler struct {
+	Storage RefreshStorage
+}
+
+// @Summary Get refresh runs
+// @Description Get runs of scheduled re-enrichment of stale and incomplete songs with changed fields, the latest first
+// @Tags enrichment
+// @ID get-refresh-runs
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Produce json
+// @Success 200 {object} song.Response{response=[]enrich.RefreshRun}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/enrichment/runs [get]
+func (rh *RefreshHandler) GetRuns(w http.ResponseWriter, r *http.Request) {
+
+	limit, offset, err := song.ReadPagination(r)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	runs, err := rh.Storage.GetRefreshRuns(limit, offset)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": runs,
+	})
+}
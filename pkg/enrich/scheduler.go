@@ -0,0 +1,248 @@
+package enrich
+
+import (
+	"SongLibrary/pkg/info"
+	"SongLibrary/pkg/song"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	FieldReleaseDate = "releaseDate"
+	FieldText        = "text"
+	FieldLink        = "link"
+)
+
+// actors of revisions made by enrichment worker and refresh scheduler, fields changed by other actors
+// except song.ActorSystem are edited by hand and are not refreshed
+const (
+	ActorEnrichment = "enrichment"
+	ActorRefresh    = "refresh"
+)
+
+// RefreshChange model info
+// @Description field of song changed by refresh run
+type RefreshChange struct {
+	SongID   int    `json:"songId"`
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// RefreshRun model info
+// @Description run of scheduled re-enrichment, checked songs were requested from info API
+type RefreshRun struct {
+	ID         int              `json:"id"`
+	StartedAt  time.Time        `json:"startedAt"`
+	FinishedAt time.Time        `json:"finishedAt"`
+	Checked    int              `json:"checked"`
+	Updated    int              `json:"updated"`
+	Failed     int              `json:"failed"`
+	Changes    []*RefreshChange `json:"changes"`
+}
+
+// RefreshStorage keeps last enrichment time of songs and reports of refresh runs
+type RefreshStorage interface {
+	//GetStaleSongs returns songs enriched before enrichedBefore and songs without release date, text or link
+	//enriched before incompleteBefore, songs never enriched go first, songs with unfinished enrichment job are skipped
+	GetStaleSongs(enrichedBefore, incompleteBefore time.Time, limit int) ([]*song.Song, error)
+	MarkEnriched(songID int) error
+	Update(id int, releaseDate, text, link string) error
+	//SaveRefreshRun stores run with its changes and sets run.ID
+	SaveRefreshRun(run *RefreshRun) error
+	//GetRefreshRuns returns runs with changes, the latest first
+	GetRefreshRuns(limit, offset int) ([]*RefreshRun, error)
+}
+
+// Scheduler requests stale and incomplete songs from info API every Interval, at most BatchSize songs per run
+// and not more often than once per RateLimit, so info API is not flooded. Incomplete songs are requested
+// again after IncompleteMinAge, complete ones after MaxAge. When Storage keeps revisions,
+// fields changed by hand are not replaced by values of info API
+type Scheduler struct {
+	Storage          RefreshStorage
+	Info             info.MetadataProvider
+	Interval         time.Duration
+	MaxAge           time.Duration
+	IncompleteMinAge time.Duration
+	BatchSize        int
+	RateLimit        time.Duration
+}
+
+// Run starts refresh runs until ctx is done, runs without checked songs are not saved
+func (s *Scheduler) Run(ctx context.Context) {
+
+	for {
+		run, err := s.RunOnce(ctx)
+		if err != nil {
+			log.Printf("refresh run error: [%s]\n", err.Error())
+		} else if run.Checked > 0 {
+			log.Printf("refresh run done, id: [%d], checked: [%d], updated: [%d], failed: [%d]\n", run.ID, run.Checked, run.Updated, run.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.Interval):
+		}
+	}
+}
+
+// RunOnce refreshes one batch of songs
+func (s *Scheduler) RunOnce(ctx context.Context) (*RefreshRun, error) {
+
+	run := &RefreshRun{
+		StartedAt: time.Now(),
+		Changes:   make([]*RefreshChange, 0),
+	}
+
+	songs, err := s.Storage.GetStaleSongs(run.StartedAt.Add(-s.MaxAge), run.StartedAt.Add(-s.IncompleteMinAge), s.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter <-chan time.Time
+	if s.RateLimit > 0 {
+		ticker := time.NewTicker(s.RateLimit)
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	for i, item := range songs {
+		if i > 0 && limiter != nil {
+			select {
+			case <-ctx.Done():
+			case <-limiter:
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		run.Checked++
+		changes, err := s.refresh(ctx, item)
+		if err != nil {
+			log.Printf("refresh song error: [%s], song id: [%d]\n", err.Error(), item.ID)
+			run.Failed++
+			continue
+		}
+		if len(changes) > 0 {
+			run.Updated++
+			run.Changes = append(run.Changes, changes...)
+		}
+	}
+	run.FinishedAt = time.Now()
+
+	if run.Checked == 0 {
+		return run, nil
+	}
+
+	err = s.Storage.SaveRefreshRun(run)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// refresh requests song from info API and stores changed fields, empty fields of answer do not clear stored ones
+func (s *Scheduler) refresh(ctx context.Context, item *song.Song) ([]*RefreshChange, error) {
+
+	detail, err := s.Info.Fetch(ctx, item.Group, item.Name)
+	if errors.Is(err, info.ErrNotFound) {
+		return nil, s.Storage.MarkEnriched(item.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes := Changes(item, detail)
+	if len(changes) > 0 {
+		changes, err = s.withoutManual(item.ID, changes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(changes) > 0 {
+		update := map[string]string{}
+		for _, change := range changes {
+			update[change.Field] = change.NewValue
+		}
+
+		err = s.Storage.Update(item.ID, update[FieldReleaseDate], update[FieldText], update[FieldLink])
+		if errors.Is(err, song.ErrNotFound) {
+			//song is deleted while request was running
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, s.Storage.MarkEnriched(item.ID)
+}
+
+// withoutManual drops changes of fields which were changed by hand in revisions of song
+func (s *Scheduler) withoutManual(songID int, changes []*RefreshChange) ([]*RefreshChange, error) {
+
+	revisioner, ok := s.Storage.(song.Revisioner)
+	if !ok {
+		return changes, nil
+	}
+
+	revisions, err := revisioner.GetRevisions(songID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	manual := map[string]bool{}
+	for _, revision := range revisions {
+		if revision.Actor == song.ActorSystem || revision.Actor == ActorEnrichment || revision.Actor == ActorRefresh {
+			continue
+		}
+		for _, change := range revision.Changes {
+			manual[change.Field] = true
+		}
+	}
+
+	res := make([]*RefreshChange, 0, len(changes))
+	for _, change := range changes {
+		if manual[change.Field] {
+			log.Printf("refresh skips field changed by hand, song id: [%d], field: [%s], value of info API: [%s]\n", songID, change.Field, change.NewValue)
+			continue
+		}
+		res = append(res, change)
+	}
+
+	return res, nil
+}
+
+// Changes returns fields of detail which are not empty and differ from stored ones
+func Changes(item *song.Song, detail *info.Detail) []*RefreshChange {
+
+	fields := []struct {
+		name     string
+		oldValue string
+		newValue string
+	}{
+		{FieldReleaseDate, item.ReleaseDate, detail.ReleaseDate},
+		{FieldText, item.Text, detail.Text},
+		{FieldLink, item.Link, detail.Link},
+	}
+
+	changes := make([]*RefreshChange, 0)
+	for _, field := range fields {
+		if field.newValue == "" || field.newValue == field.oldValue {
+			continue
+		}
+		changes = append(changes, &RefreshChange{
+			SongID:   item.ID,
+			Field:    field.name,
+			OldValue: field.oldValue,
+			NewValue: field.newValue,
+		})
+	}
+
+	return changes
+}
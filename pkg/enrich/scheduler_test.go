@@ -0,0 +1,209 @@
+package enrich_test
+
+import (
+	"SongLibrary/pkg/enrich"
+	"SongLibrary/pkg/info"
+	"SongLibrary/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeProvider answers with details by song name, unknown songs are not found, calls are recorded with time
+type fakeProvider struct {
+	details map[string]*info.Detail
+	errs    map[string]error
+	calls   []time.Time
+}
+
+func (p *fakeProvider) Fetch(ctx context.Context, group, songName string) (*info.Detail, error) {
+
+	p.calls = append(p.calls, time.Now())
+
+	if err := p.errs[songName]; err != nil {
+		return nil, err
+	}
+	detail, ok := p.details[songName]
+	if !ok {
+		return nil, info.ErrNotFound
+	}
+	res := *detail
+	return &res, nil
+}
+
+func newScheduler(t *testing.T, memory *storage.MemoryStorage, provider *fakeProvider) *enrich.Scheduler {
+
+	t.Helper()
+
+	refreshStorage, ok := memory.As(enrich.ActorRefresh).(enrich.RefreshStorage)
+	if !ok {
+		t.Fatal("memory storage is not refresh storage")
+	}
+	return &enrich.Scheduler{
+		Storage:          refreshStorage,
+		Info:             provider,
+		MaxAge:           time.Hour,
+		IncompleteMinAge: time.Hour,
+		BatchSize:        10,
+	}
+}
+
+func TestSchedulerRunOnce(t *testing.T) {
+
+	memory := storage.NewMemoryStorage()
+	provider := &fakeProvider{
+		details: map[string]*info.Detail{
+			"uprising":  {ReleaseDate: "07.09.2009", Text: "uprising text", Link: "uprising link"},
+			"starlight": {Text: "starlight text", Link: "api link"},
+		},
+		errs: map[string]error{"hysteria": errors.New("info API is down")},
+	}
+
+	uprising, err := memory.Add("uprising", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	//link of starlight is set by hand, so it is kept
+	starlight, err := memory.As("curator").Add("starlight", "muse", "", "", "curator link")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	_, err = memory.Add("unknown", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	_, err = memory.Add("hysteria", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+
+	scheduler := newScheduler(t, memory, provider)
+	run, err := scheduler.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if run.ID == 0 || run.Checked != 4 || run.Updated != 2 || run.Failed != 1 {
+		t.Errorf("run is %+v, want saved run with 4 checked, 2 updated and 1 failed songs", run)
+	}
+
+	changes := make([]string, 0, len(run.Changes))
+	for _, change := range run.Changes {
+		changes = append(changes, fmt.Sprintf("%d %s [%s] -> [%s]", change.SongID, change.Field, change.OldValue, change.NewValue))
+	}
+	want := []string{
+		fmt.Sprintf("%d releaseDate [] -> [07.09.2009]", uprising),
+		fmt.Sprintf("%d text [] -> [uprising text]", uprising),
+		fmt.Sprintf("%d link [] -> [uprising link]", uprising),
+		fmt.Sprintf("%d text [] -> [starlight text]", starlight),
+	}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("changes are %v, want %v", changes, want)
+	}
+
+	item, err := memory.GetSong(starlight)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if item.Text != "starlight text" || item.Link != "curator link" {
+		t.Errorf("starlight is %+v, want text of info API and link of curator", item)
+	}
+
+	runs, err := memory.GetRefreshRuns(0, 0)
+	if err != nil {
+		t.Fatalf("get runs error: %v", err)
+	}
+	if len(runs) != 1 || len(runs[0].Changes) != len(want) {
+		t.Errorf("saved runs are %+v, want one run with %d changes", runs, len(want))
+	}
+}
+
+func TestSchedulerIncompleteMinAge(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		incompleteMinAge time.Duration
+		wantChecked      int
+	}{
+		//starlight has no release date, failed hysteria is never enriched, so it is requested again
+		{"incomplete song enriched recently is skipped", time.Hour, 1},
+		{"incomplete song is requested after min age", time.Nanosecond, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			memory := storage.NewMemoryStorage()
+			provider := &fakeProvider{
+				details: map[string]*info.Detail{"starlight": {Text: "text", Link: "link"}},
+				errs:    map[string]error{"hysteria": errors.New("info API is down")},
+			}
+			for _, name := range []string{"starlight", "hysteria"} {
+				_, err := memory.Add(name, "muse", "", "", "")
+				if err != nil {
+					t.Fatalf("add error: %v", err)
+				}
+			}
+
+			scheduler := newScheduler(t, memory, provider)
+			scheduler.IncompleteMinAge = tt.incompleteMinAge
+			_, err := scheduler.RunOnce(context.Background())
+			if err != nil {
+				t.Fatalf("first run error: %v", err)
+			}
+			time.Sleep(time.Millisecond)
+
+			run, err := scheduler.RunOnce(context.Background())
+			if err != nil {
+				t.Fatalf("second run error: %v", err)
+			}
+			if run.Checked != tt.wantChecked {
+				t.Errorf("second run checked %d songs, want %d", run.Checked, tt.wantChecked)
+			}
+			if calls := len(provider.calls); calls != 2+tt.wantChecked {
+				t.Errorf("info API calls: got %d, want %d", calls, 2+tt.wantChecked)
+			}
+		})
+	}
+}
+
+func TestSchedulerRateLimit(t *testing.T) {
+
+	memory := storage.NewMemoryStorage()
+	provider := &fakeProvider{}
+	for _, name := range []string{"uprising", "starlight", "hysteria"} {
+		_, err := memory.Add(name, "muse", "", "", "")
+		if err != nil {
+			t.Fatalf("add error: %v", err)
+		}
+	}
+
+	scheduler := newScheduler(t, memory, provider)
+	scheduler.RateLimit = 50 * time.Millisecond
+	_, err := scheduler.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+
+	if len(provider.calls) != 3 {
+		t.Fatalf("info API calls: got %d, want 3", len(provider.calls))
+	}
+	for i := 1; i < len(provider.calls); i++ {
+		if gap := provider.calls[i].Sub(provider.calls[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("gap between calls %d and %d is %v, want at least rate limit", i, i+1, gap)
+		}
+	}
+
+	//cancelled run stops waiting for the next call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scheduler.MaxAge = 0
+	run, err := scheduler.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("cancelled run error: %v", err)
+	}
+	if run.Checked != 0 || len(provider.calls) != 3 {
+		t.Errorf("cancelled run checked %d songs with %d calls, want no calls", run.Checked, len(provider.calls)-3)
+	}
+}
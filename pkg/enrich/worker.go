@@ -0,0 +1,118 @@
+package enrich
+
+import (
+	"SongLibrary/pkg/info"
+	"SongLibrary/pkg/song"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Job is claimed enrichment job, Attempts includes the current one
+type Job struct {
+	SongID   int
+	Song     string
+	Group    string
+	Attempts int
+}
+
+// Storage keeps enrichment jobs, claimed job is running until it is completed, retried or failed
+type Storage interface {
+	//ClaimEnrichment marks the oldest ready pending job as running, running jobs not updated for staleAfter
+	//are claimed again, nil job means nothing to do
+	ClaimEnrichment(staleAfter time.Duration) (*Job, error)
+	CompleteEnrichment(songID int) error
+	RetryEnrichment(songID int, nextRunAt time.Time, reason string) error
+	FailEnrichment(songID int, reason string) error
+	Update(id int, releaseDate, text, link string) error
+}
+
+type Worker struct {
+	Storage      Storage
+	Info         info.MetadataProvider
+	PollInterval time.Duration
+	MaxAttempts  int
+	Backoff      time.Duration
+	MaxBackoff   time.Duration
+	StaleAfter   time.Duration
+}
+
+// Run processes jobs until ctx is done, it sleeps for PollInterval when there are no ready jobs
+func (w *Worker) Run(ctx context.Context) {
+
+	for {
+		job, err := w.Storage.ClaimEnrichment(w.StaleAfter)
+		if err != nil {
+			log.Printf("claim enrichment job error: [%s]\n", err.Error())
+		}
+
+		if job != nil {
+			w.process(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context, job *Job) {
+
+	detail, err := w.Info.Fetch(ctx, job.Group, job.Song)
+	if err == nil {
+		err = w.apply(job.SongID, detail)
+	}
+
+	switch {
+	case err == nil:
+		err = w.Storage.CompleteEnrichment(job.SongID)
+		log.Printf("enrichment done, song id: [%d], attempt: [%d]\n", job.SongID, job.Attempts)
+
+	//unknown song and bad data from info API are not fixed by retries
+	case errors.Is(err, info.ErrNotFound) || errors.Is(err, song.ErrValidation) || job.Attempts >= w.MaxAttempts:
+		log.Printf("enrichment failed, song id: [%d], attempt: [%d], error: [%s]\n", job.SongID, job.Attempts, err.Error())
+		err = w.Storage.FailEnrichment(job.SongID, err.Error())
+
+	default:
+		delay := Backoff(job.Attempts, w.Backoff, w.MaxBackoff)
+		log.Printf("enrichment retry, song id: [%d], attempt: [%d], delay: [%s], error: [%s]\n", job.SongID, job.Attempts, delay, err.Error())
+		err = w.Storage.RetryEnrichment(job.SongID, time.Now().Add(delay), err.Error())
+	}
+
+	if err != nil {
+		log.Printf("save enrichment job error: [%s], song id: [%d]\n", err.Error(), job.SongID)
+	}
+}
+
+// apply stores non-empty fields of detail, song may be deleted while job was running
+func (w *Worker) apply(songID int, detail *info.Detail) error {
+
+	if detail.ReleaseDate == "" && detail.Text == "" && detail.Link == "" {
+		return nil
+	}
+
+	err := w.Storage.Update(songID, detail.ReleaseDate, detail.Text, detail.Link)
+	if errors.Is(err, song.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// Backoff returns delay before retry after attempt: base, 2*base, 4*base and so on, but not more than max
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+	return delay
+}
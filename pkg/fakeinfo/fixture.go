@@ -0,0 +1,61 @@
+package fakeinfo
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Fixture is content of fixture file, Latency and ErrorRate are defaults for songs without own values
+type Fixture struct {
+	Latency   time.Duration  `mapstructure:"latency"`
+	ErrorRate float64        `mapstructure:"errorRate"`
+	Songs     []*FixtureSong `mapstructure:"songs"`
+}
+
+// FixtureSong is answer for one song. Status other than 200 is sent with empty body,
+// Body is sent instead of SongDetail json as is, so malformed answers can be tested.
+// ErrorRate is probability from 0 to 1 of answering 500 instead.
+type FixtureSong struct {
+	Group       string         `mapstructure:"group"`
+	Song        string         `mapstructure:"song"`
+	ReleaseDate string         `mapstructure:"releaseDate"`
+	Text        string         `mapstructure:"text"`
+	Link        string         `mapstructure:"link"`
+	Status      int            `mapstructure:"status"`
+	Body        string         `mapstructure:"body"`
+	Latency     *time.Duration `mapstructure:"latency"`
+	ErrorRate   *float64       `mapstructure:"errorRate"`
+}
+
+// ReadFixture reads json or yaml fixture, format is chosen by file extension
+func ReadFixture(path string) (*Fixture, error) {
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{}
+	err = v.Unmarshal(fixture)
+	if err != nil {
+		return nil, err
+	}
+
+	return fixture, nil
+}
+
+// Find looks up song by group and song names without case
+func (f *Fixture) Find(group, songName string) *FixtureSong {
+
+	for _, item := range f.Songs {
+		if strings.EqualFold(item.Group, group) && strings.EqualFold(item.Song, songName) {
+			return item
+		}
+	}
+
+	return nil
+}
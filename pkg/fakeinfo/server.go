@@ -0,0 +1,90 @@
+package fakeinfo
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Server is info API for development and e2e tests: GET /info?group=...&song=... answers from Fixture,
+// unknown songs get 404
+type Server struct {
+	Fixture *Fixture
+}
+
+// Mux returns handler with routes of info API
+func (s *Server) Mux() *http.ServeMux {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", s.Info)
+
+	return mux
+}
+
+func (s *Server) Info(w http.ResponseWriter, r *http.Request) {
+
+	group := r.URL.Query().Get("group")
+	songName := r.URL.Query().Get("song")
+
+	item := s.Fixture.Find(group, songName)
+	if item == nil {
+		log.Printf("song is not found, group: [%s], song: [%s]\n", group, songName)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	latency := s.Fixture.Latency
+	if item.Latency != nil {
+		latency = *item.Latency
+	}
+	errorRate := s.Fixture.ErrorRate
+	if item.ErrorRate != nil {
+		errorRate = *item.ErrorRate
+	}
+
+	//client may give up before latency is over
+	select {
+	case <-time.After(latency):
+	case <-r.Context().Done():
+		log.Printf("request canceled, group: [%s], song: [%s]\n", group, songName)
+		return
+	}
+
+	status := http.StatusOK
+	if item.Status != 0 {
+		status = item.Status
+	}
+	if errorRate > 0 && rand.Float64() < errorRate {
+		status = http.StatusInternalServerError
+	}
+
+	if status != http.StatusOK {
+		log.Printf("answer status: [%d], group: [%s], song: [%s]\n", status, group, songName)
+		w.WriteHeader(status)
+		return
+	}
+
+	if item.Body != "" {
+		log.Printf("answer raw body, group: [%s], song: [%s]\n", group, songName)
+		w.Write([]byte(item.Body))
+		return
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"SongDetail": map[string]string{
+			"releaseDate": item.ReleaseDate,
+			"text":        item.Text,
+			"link":        item.Link,
+		},
+	})
+	if err != nil {
+		log.Printf("marshal answer error: [%s]\n", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
@@ -0,0 +1,215 @@
+package group
+
+import (
+	"SongLibrary/pkg/song"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// @Summary Get groups list
+// @Description Get groups list with pagination and filtering by name, sorted by name
+// @Tags groups
+// @ID get-groups
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Param name query string false "group name fragment, case insensitive"
+// @Produce json
+// @Success 200 {object} song.Response{response=[]group.Group}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/groups [get]
+func (gh *GroupHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	limit, offset, err := song.ReadPagination(r)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	groups, err := gh.Storage.GetGroups(limit, offset, r.FormValue("name"))
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": groups,
+	})
+}
+
+// @Summary Get group
+// @Description Get group with number of its songs
+// @Tags groups
+// @ID get-group
+// @Param id path int true "group id"
+// @Produce json
+// @Success 200 {object} song.Response{response=group.Group}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/groups/{id} [get]
+func (gh *GroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+
+	id, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	group, err := gh.Storage.GetGroup(id)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": group,
+	})
+}
+
+// @Summary Get songs of group
+// @Description Get songs of group with pagination
+// @Tags groups
+// @ID get-group-songs
+// @Param id path int true "group id"
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Produce json
+// @Success 200 {object} song.Response{response=[]song.Song}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/groups/{id}/songs [get]
+func (gh *GroupHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
+
+	id, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	limit, offset, err := song.ReadPagination(r)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	songs, err := gh.Storage.GetGroupSongs(id, limit, offset)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": songs,
+	})
+}
+
+// @Summary Add new group
+// @Description Add new group, names are unique ignoring case
+// @Tags groups
+// @ID new-group
+// @Param bodyJSON body string true "group name" SchemaExample({"name":"some group name"})
+// @Accept json
+// @Produce json
+// @Success 201 {object} song.Response{response=song.Response{id=int}}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/groups [put]
+func (gh *GroupHandler) New(w http.ResponseWriter, r *http.Request) {
+
+	group := &Group{}
+	err := song.ReadJSON(r, group)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		song.WriteError(w, r, song.NewValidationError("name", "must be not empty"))
+		return
+	}
+
+	group.ID, err = gh.Storage.AddGroup(group.Name)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusCreated, song.Response{
+		"response": song.Response{
+			"id": group.ID,
+		},
+	})
+}
+
+// @Summary Rename group
+// @Description Rename group, songs of the group get new group name
+// @Tags groups
+// @ID update-group
+// @Param bodyJSON body string true "group id and new name" SchemaExample({"id":2,"name":"new group name"})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/groups [post]
+func (gh *GroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+
+	group := &Group{}
+	err := song.ReadJSON(r, group)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		song.WriteError(w, r, song.NewValidationError("name", "must be not empty"))
+		return
+	}
+
+	err = gh.Storage.RenameGroup(group.ID, group.Name)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("group renamed, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", group.ID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+// @Summary Delete group
+// @Description Delete group without songs and albums, songs of group in trash are purged with it
+// @Tags groups
+// @ID delete-group
+// @Param bodyJSON body string true "group id" SchemaExample({"id":2})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/groups [delete]
+func (gh *GroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	group := &Group{}
+	err := song.ReadJSON(r, group)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	err = gh.Storage.DeleteGroup(group.ID)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("group deleted, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", group.ID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
@@ -0,0 +1,26 @@
+package group
+
+import (
+	"SongLibrary/pkg/song"
+)
+
+// Group model info
+// @Description group information
+type Group struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	SongsCount int    `json:"songsCount"`
+}
+
+type GroupHandler struct {
+	Storage
+}
+
+type Storage interface {
+	AddGroup(name string) (int, error)
+	DeleteGroup(id int) error
+	RenameGroup(id int, name string) error
+	GetGroup(id int) (*Group, error)
+	GetGroups(limit, offset int, nameFragment string) ([]*Group, error)
+	GetGroupSongs(id, limit, offset int) ([]*song.Song, error)
+}
@@ -0,0 +1,137 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// ErrCircuitOpen is returned without calling provider while breaker is open
+var ErrCircuitOpen = errors.New("info API circuit breaker is open")
+
+// BreakerState model info
+// @Description state of circuit breaker of info API, failures are consecutive
+type BreakerState struct {
+	Name     string    `json:"name"`
+	State    string    `json:"state"`
+	Failures int       `json:"failures"`
+	OpenedAt time.Time `json:"openedAt,omitempty"`
+}
+
+// Breaker stops calling Provider after FailureThreshold consecutive failures, after OpenTimeout
+// the only probe request is let through: its success closes breaker, its failure opens it again.
+// Unknown song and rejections of bulkhead are not failures of provider. Results of calls which started
+// before breaker last opened are ignored, so a slow call cannot close breaker opened after it started.
+type Breaker struct {
+	Name             string
+	Provider         MetadataProvider
+	FailureThreshold int
+	OpenTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	//generation grows every time breaker opens
+	generation int
+}
+
+func NewBreaker(name string, provider MetadataProvider, failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		Name:             name,
+		Provider:         provider,
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		state:            BreakerClosed,
+	}
+}
+
+func (b *Breaker) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+
+	generation, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := b.Provider.Fetch(ctx, group, songName)
+	b.record(generation, err)
+
+	return detail, err
+}
+
+func (b *Breaker) State() BreakerState {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BreakerState{
+		Name:     b.Name,
+		State:    b.state,
+		Failures: b.failures,
+		OpenedAt: b.openedAt,
+	}
+}
+
+// allow returns generation of breaker at the start of call
+func (b *Breaker) allow() (int, error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.OpenTimeout {
+			return 0, ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+	case BreakerHalfOpen:
+		//probe is already running
+		return 0, ErrCircuitOpen
+	}
+
+	return b.generation, nil
+}
+
+func (b *Breaker) record(generation int, err error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	if err == nil || errors.Is(err, ErrNotFound) {
+		b.state = BreakerClosed
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	if errors.Is(err, ErrBulkheadFull) {
+		//probe is not sent, so the next one waits for the open timeout again
+		if b.state == BreakerHalfOpen {
+			b.open()
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.FailureThreshold {
+		b.open()
+	}
+}
+
+// open must be called with b.mu held
+func (b *Breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.generation++
+}
@@ -0,0 +1,124 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUpstream = errors.New("upstream is down")
+
+func TestBreakerOpensAfterFailures(t *testing.T) {
+
+	provider := &fakeProvider{err: errUpstream}
+	breaker := NewBreaker("fake", provider, 3, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := breaker.Fetch(context.Background(), "muse", "uprising"); !errors.Is(err, errUpstream) {
+			t.Fatalf("call %d: got %v, want %v", i+1, err, errUpstream)
+		}
+	}
+	if state := breaker.State(); state.State != BreakerOpen || state.Failures != 3 || state.OpenedAt.IsZero() {
+		t.Fatalf("after failures: got %+v", state)
+	}
+
+	if _, err := breaker.Fetch(context.Background(), "muse", "uprising"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("open breaker: got %v, want %v", err, ErrCircuitOpen)
+	}
+	if calls := provider.callsCount(); calls != 3 {
+		t.Errorf("provider calls: got %d, want 3", calls)
+	}
+}
+
+func TestBreakerNotFoundIsNotFailure(t *testing.T) {
+
+	provider := &fakeProvider{err: ErrNotFound}
+	breaker := NewBreaker("fake", provider, 1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := breaker.Fetch(context.Background(), "muse", "unknown"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("call %d: got %v, want %v", i+1, err, ErrNotFound)
+		}
+	}
+	if state := breaker.State(); state.State != BreakerClosed || state.Failures != 0 {
+		t.Errorf("after unknown songs: got %+v", state)
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probeErr  error
+		wantState string
+	}{
+		{"probe success closes", nil, BreakerClosed},
+		{"probe failure opens again", errUpstream, BreakerOpen},
+		{"rejected probe opens again", ErrBulkheadFull, BreakerOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			provider := &fakeProvider{err: errUpstream}
+			breaker := NewBreaker("fake", provider, 1, 20*time.Millisecond)
+			breaker.Fetch(context.Background(), "muse", "uprising")
+			if state := breaker.State(); state.State != BreakerOpen {
+				t.Fatalf("after failure: got %+v", state)
+			}
+			time.Sleep(30 * time.Millisecond)
+
+			//probe is held by provider, so other calls see half-open breaker
+			provider.err = tt.probeErr
+			provider.detail = &Detail{Link: "some link"}
+			provider.started = make(chan struct{})
+			provider.release = make(chan struct{})
+			done := make(chan error)
+			go func() {
+				_, err := breaker.Fetch(context.Background(), "muse", "uprising")
+				done <- err
+			}()
+			<-provider.started
+
+			if state := breaker.State(); state.State != BreakerHalfOpen {
+				t.Errorf("during probe: got %+v", state)
+			}
+			if _, err := breaker.Fetch(context.Background(), "muse", "uprising"); !errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("call during probe: got %v, want %v", err, ErrCircuitOpen)
+			}
+
+			close(provider.release)
+			if err := <-done; !errors.Is(err, tt.probeErr) {
+				t.Errorf("probe: got %v, want %v", err, tt.probeErr)
+			}
+			if state := breaker.State(); state.State != tt.wantState {
+				t.Errorf("after probe: got %+v, want state %s", state, tt.wantState)
+			}
+
+			//breaker opened again waits for the whole open timeout before the next probe
+			if tt.wantState == BreakerOpen {
+				if _, err := breaker.Fetch(context.Background(), "muse", "uprising"); !errors.Is(err, ErrCircuitOpen) {
+					t.Errorf("call after failed probe: got %v, want %v", err, ErrCircuitOpen)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerIgnoresCallsStartedBeforeOpen(t *testing.T) {
+
+	provider := &fakeProvider{err: errUpstream}
+	breaker := NewBreaker("fake", provider, 1, time.Hour)
+
+	//slow call starts while breaker is closed and ends after another call opened it
+	generation, err := breaker.allow()
+	if err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	breaker.Fetch(context.Background(), "muse", "uprising")
+	breaker.record(generation, nil)
+
+	if state := breaker.State(); state.State != BreakerOpen || state.Failures != 1 {
+		t.Errorf("after success of call started before open: got %+v, want open breaker", state)
+	}
+}
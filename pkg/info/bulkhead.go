@@ -0,0 +1,35 @@
+package info
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBulkheadFull is returned without calling provider when all slots are taken
+var ErrBulkheadFull = errors.New("too many concurrent requests to info API")
+
+// Bulkhead limits number of concurrent calls of Provider, extra calls are rejected instead of waiting,
+// so slow info API does not hold all workers
+type Bulkhead struct {
+	Provider MetadataProvider
+	slots    chan struct{}
+}
+
+func NewBulkhead(provider MetadataProvider, maxConcurrent int) *Bulkhead {
+	return &Bulkhead{
+		Provider: provider,
+		slots:    make(chan struct{}, maxConcurrent),
+	}
+}
+
+func (b *Bulkhead) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+
+	select {
+	case b.slots <- struct{}{}:
+	default:
+		return nil, ErrBulkheadFull
+	}
+	defer func() { <-b.slots }()
+
+	return b.Provider.Fetch(ctx, group, songName)
+}
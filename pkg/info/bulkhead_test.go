@@ -0,0 +1,46 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBulkheadRejectsExtraCalls(t *testing.T) {
+
+	provider := &fakeProvider{
+		detail:  &Detail{Link: "some link"},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	bulkhead := NewBulkhead(provider, 2)
+
+	done := make(chan error)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := bulkhead.Fetch(context.Background(), "muse", "uprising")
+			done <- err
+		}()
+		<-provider.started
+	}
+
+	if _, err := bulkhead.Fetch(context.Background(), "muse", "uprising"); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("call over limit: got %v, want %v", err, ErrBulkheadFull)
+	}
+	if calls := provider.callsCount(); calls != 2 {
+		t.Errorf("provider calls: got %d, want 2", calls)
+	}
+
+	close(provider.release)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("call within limit error: %v", err)
+		}
+	}
+
+	//slots are freed after calls
+	provider.started = nil
+	if _, err := bulkhead.Fetch(context.Background(), "muse", "uprising"); err != nil {
+		t.Errorf("call after release error: %v", err)
+	}
+}
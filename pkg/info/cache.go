@@ -0,0 +1,96 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	detail Detail
+	//notFound means that provider answered with ErrNotFound
+	notFound  bool
+	expiresAt time.Time
+}
+
+// Cache keeps answers of Provider for TTL, keys are group and song names ignoring case,
+// unknown song is cached as ErrNotFound, other errors are not cached
+type Cache struct {
+	Provider MetadataProvider
+	TTL      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	//sweptAt is time of the last removal of all expired entries, it is done at most once per TTL
+	sweptAt time.Time
+}
+
+func NewCache(provider MetadataProvider, ttl time.Duration) *Cache {
+	return &Cache{
+		Provider: provider,
+		TTL:      ttl,
+		entries:  make(map[string]*cacheEntry),
+	}
+}
+
+func (c *Cache) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+
+	key := strings.ToLower(group) + "\x00" + strings.ToLower(songName)
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !now.Before(entry.expiresAt) {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		if entry.notFound {
+			return nil, ErrNotFound
+		}
+		detail := entry.detail
+		return &detail, nil
+	}
+
+	detail, err := c.Provider.Fetch(ctx, group, songName)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	entry = &cacheEntry{
+		notFound:  err != nil,
+		expiresAt: now.Add(c.TTL),
+	}
+	if detail != nil {
+		entry.detail = *detail
+	}
+
+	c.mu.Lock()
+	if now.Sub(c.sweptAt) >= c.TTL {
+		c.removeExpired(now)
+		c.sweptAt = now
+	}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	return detail, err
+}
+
+// size returns number of kept entries, expired ones are counted until removal
+func (c *Cache) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// removeExpired scans all entries, so it is called once per TTL and expired entries which are not looked up
+// are kept at most two TTL. It must be called with c.mu held
+func (c *Cache) removeExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
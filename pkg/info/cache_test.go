@@ -0,0 +1,117 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheKeepsAnswerForTTL(t *testing.T) {
+
+	provider := &fakeProvider{detail: &Detail{ReleaseDate: "2006", Text: "text", Link: "link"}}
+	cache := NewCache(provider, 30*time.Millisecond)
+
+	for _, names := range [][2]string{{"Muse", "Uprising"}, {"muse", "uprising"}, {"MUSE", "UPRISING"}} {
+		detail, err := cache.Fetch(context.Background(), names[0], names[1])
+		if err != nil || *detail != *provider.detail {
+			t.Fatalf("fetch %v: got %+v, %v", names, detail, err)
+		}
+	}
+	if calls := provider.callsCount(); calls != 1 {
+		t.Errorf("provider calls within TTL: got %d, want 1", calls)
+	}
+
+	//answer is a copy, so caller cannot change cached detail
+	detail, _ := cache.Fetch(context.Background(), "muse", "uprising")
+	detail.Text = "changed"
+	if detail, _ := cache.Fetch(context.Background(), "muse", "uprising"); detail.Text != "text" {
+		t.Errorf("cached detail changed by caller: got %+v", detail)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, err := cache.Fetch(context.Background(), "muse", "uprising"); err != nil {
+		t.Fatalf("fetch after TTL error: %v", err)
+	}
+	if calls := provider.callsCount(); calls != 2 {
+		t.Errorf("provider calls after TTL: got %d, want 2", calls)
+	}
+}
+
+func TestCacheNegative(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{"unknown song is cached", ErrNotFound, 1},
+		{"failure is not cached", errUpstream, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			provider := &fakeProvider{err: tt.err}
+			cache := NewCache(provider, time.Hour)
+
+			for i := 0; i < 3; i++ {
+				if _, err := cache.Fetch(context.Background(), "muse", "unknown"); !errors.Is(err, tt.err) {
+					t.Fatalf("fetch %d: got %v, want %v", i+1, err, tt.err)
+				}
+			}
+			if calls := provider.callsCount(); calls != tt.wantCalls {
+				t.Errorf("provider calls: got %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheRemovesExpired(t *testing.T) {
+
+	provider := &fakeProvider{detail: &Detail{Link: "link"}}
+	cache := NewCache(provider, 20*time.Millisecond)
+
+	for _, songName := range []string{"uprising", "madness", "starlight"} {
+		cache.Fetch(context.Background(), "muse", songName)
+	}
+	if size := cache.size(); size != 3 {
+		t.Fatalf("entries: got %d, want 3", size)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	cache.Fetch(context.Background(), "muse", "hysteria")
+	if size := cache.size(); size != 1 {
+		t.Errorf("entries after expiration: got %d, want 1", size)
+	}
+}
+
+func TestCacheSweepsOncePerTTL(t *testing.T) {
+
+	provider := &fakeProvider{detail: &Detail{Link: "link"}}
+	cache := NewCache(provider, 100*time.Millisecond)
+
+	for _, songName := range []string{"uprising", "madness", "starlight"} {
+		cache.Fetch(context.Background(), "muse", songName)
+		time.Sleep(60 * time.Millisecond)
+	}
+	//uprising is removed by the sweep when starlight is added
+	if size := cache.size(); size != 2 {
+		t.Fatalf("entries after sweep: got %d, want 2", size)
+	}
+
+	//madness is expired, but entries were swept less than TTL ago, so the miss does not scan them
+	cache.Fetch(context.Background(), "muse", "hysteria")
+	if size := cache.size(); size != 3 {
+		t.Fatalf("entries after miss within TTL of sweep: got %d, want 3", size)
+	}
+
+	//expired entry is removed when it is looked up, failed answer is not cached instead of it
+	provider.err = errUpstream
+	cache.Fetch(context.Background(), "muse", "madness")
+	if calls := provider.callsCount(); calls != 5 {
+		t.Errorf("provider calls: got %d, want 5", calls)
+	}
+	if size := cache.size(); size != 2 {
+		t.Errorf("entries after lookup of expired entry: got %d, want 2", size)
+	}
+}
@@ -0,0 +1,122 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Source is provider of chain with its own timeout, zero timeout means no timeout,
+// Breaker is set for sources created by ParseSources
+type Source struct {
+	Name     string
+	Provider MetadataProvider
+	Timeout  time.Duration
+	Breaker  *Breaker
+}
+
+// SourceOptions are settings of HTTPProvider sources, Timeout is used for urls without own timeout
+type SourceOptions struct {
+	Timeout            time.Duration
+	ConnectTimeout     time.Duration
+	ReadTimeout        time.Duration
+	BreakerFailures    int
+	BreakerOpenTimeout time.Duration
+	MaxConcurrent      int
+}
+
+// Chain asks providers in order and merges their answers, fields found by earlier providers win,
+// later providers are not asked when all fields are known
+type Chain struct {
+	Sources []*Source
+}
+
+func (c *Chain) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+
+	detail := &Detail{}
+	found := false
+	errs := make([]error, 0)
+
+	for _, source := range c.Sources {
+		sourceDetail, err := c.fetch(ctx, source, group, songName)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				log.Printf("info provider error: [%s], provider: [%s], group: [%s], song: [%s]\n", err.Error(), source.Name, group, songName)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name, err))
+			continue
+		}
+
+		found = true
+		detail.merge(sourceDetail)
+		if detail.complete() {
+			break
+		}
+	}
+
+	if found {
+		return detail, nil
+	}
+
+	//the song is unknown only if every provider said so, otherwise it is worth retrying
+	for _, err := range errs {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, errors.Join(errs...)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (c *Chain) fetch(ctx context.Context, source *Source, group, songName string) (*Detail, error) {
+
+	if source.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.Timeout)
+		defer cancel()
+	}
+
+	return source.Provider.Fetch(ctx, group, songName)
+}
+
+// ParseSources reads comma separated list of info API urls for HTTPProvider chain, url may be followed by "|timeout",
+// for example "http://127.0.0.1:8082|5s,http://127.0.0.1:8083". Every provider gets its own circuit breaker
+// and bulkhead, breaker is outer one, so open breaker fails fast without taking a slot.
+func ParseSources(spec string, options SourceOptions) ([]*Source, error) {
+
+	sources := make([]*Source, 0)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		baseURL, timeoutValue, hasTimeout := strings.Cut(item, "|")
+		timeout := options.Timeout
+		if hasTimeout {
+			var err error
+			timeout, err = time.ParseDuration(strings.TrimSpace(timeoutValue))
+			if err != nil {
+				return nil, fmt.Errorf("bad timeout of info provider [%s]: %w", baseURL, err)
+			}
+		}
+
+		baseURL = strings.TrimSpace(baseURL)
+		provider := NewBulkhead(NewHTTPProvider(baseURL, options.ConnectTimeout, options.ReadTimeout), options.MaxConcurrent)
+		breaker := NewBreaker(baseURL, provider, options.BreakerFailures, options.BreakerOpenTimeout)
+		sources = append(sources, &Source{
+			Name:     baseURL,
+			Provider: breaker,
+			Timeout:  timeout,
+			Breaker:  breaker,
+		})
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.New("no info providers")
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,134 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChainFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []*fakeProvider
+		want      *Detail
+		wantErr   error
+		wantCalls []int
+	}{
+		{
+			name: "first complete answer stops chain",
+			providers: []*fakeProvider{
+				{detail: &Detail{ReleaseDate: "16.07.2006", Text: "first text", Link: "first link"}},
+				{detail: &Detail{Text: "second text"}},
+			},
+			want:      &Detail{ReleaseDate: "16.07.2006", Text: "first text", Link: "first link"},
+			wantCalls: []int{1, 0},
+		},
+		{
+			name: "fields of earlier providers win",
+			providers: []*fakeProvider{
+				{detail: &Detail{Text: "first text"}},
+				{detail: &Detail{Text: "second text", Link: "second link"}},
+				{detail: &Detail{ReleaseDate: "2006", Link: "third link"}},
+			},
+			want:      &Detail{ReleaseDate: "2006", Text: "first text", Link: "second link"},
+			wantCalls: []int{1, 1, 1},
+		},
+		{
+			name: "failed provider is skipped",
+			providers: []*fakeProvider{
+				{err: errUpstream},
+				{err: ErrNotFound},
+				{detail: &Detail{ReleaseDate: "2006", Text: "text", Link: "link"}},
+			},
+			want:      &Detail{ReleaseDate: "2006", Text: "text", Link: "link"},
+			wantCalls: []int{1, 1, 1},
+		},
+		{
+			name:      "unknown by every provider",
+			providers: []*fakeProvider{{err: ErrNotFound}, {err: ErrNotFound}},
+			wantErr:   ErrNotFound,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "failure is kept to retry",
+			providers: []*fakeProvider{{err: ErrNotFound}, {err: errUpstream}},
+			wantErr:   errUpstream,
+			wantCalls: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			chain := &Chain{}
+			for i, provider := range tt.providers {
+				chain.Sources = append(chain.Sources, &Source{Name: string(rune('a' + i)), Provider: provider})
+			}
+
+			detail, err := chain.Fetch(context.Background(), "muse", "uprising")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || *detail != *tt.want {
+				t.Errorf("got %+v, %v, want %+v", detail, err, *tt.want)
+			}
+
+			for i, provider := range tt.providers {
+				if calls := provider.callsCount(); calls != tt.wantCalls[i] {
+					t.Errorf("calls of provider %d: got %d, want %d", i, calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+// slowProvider answers after ctx is done
+type slowProvider struct{}
+
+func (slowProvider) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestChainSourceTimeout(t *testing.T) {
+
+	next := &fakeProvider{detail: &Detail{ReleaseDate: "2006", Text: "text", Link: "link"}}
+	chain := &Chain{Sources: []*Source{
+		{Name: "slow", Provider: slowProvider{}, Timeout: 10 * time.Millisecond},
+		{Name: "next", Provider: next},
+	}}
+
+	start := time.Now()
+	detail, err := chain.Fetch(context.Background(), "muse", "uprising")
+	if err != nil || detail.Link != "link" {
+		t.Errorf("got %+v, %v", detail, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("slow provider was waited for %s", elapsed)
+	}
+}
+
+func TestParseSources(t *testing.T) {
+
+	sources, err := ParseSources(" http://a:8082|5s, http://b:8083 ,", SourceOptions{Timeout: time.Second, BreakerFailures: 1, MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("got %d sources, want 2", len(sources))
+	}
+	if sources[0].Name != "http://a:8082" || sources[0].Timeout != 5*time.Second || sources[0].Breaker == nil {
+		t.Errorf("first source: got %+v", *sources[0])
+	}
+	if sources[1].Name != "http://b:8083" || sources[1].Timeout != time.Second {
+		t.Errorf("second source: got %+v", *sources[1])
+	}
+
+	for _, spec := range []string{"", " , ", "http://a:8082|soon"} {
+		if _, err := ParseSources(spec, SourceOptions{}); err == nil {
+			t.Errorf("spec [%s]: want error", spec)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package info
+
+import (
+	"SongLibrary/pkg/song"
+	"net/http"
+)
+
+type BreakerHandler struct {
+	Sources []*Source
+}
+
+// @Summary Get info API breakers
+// @Description Get circuit breaker state of every info API in fallback order, degraded is true if any breaker is not closed
+// @Tags enrichment
+// @ID get-breakers
+// @Produce json
+// @Success 200 {object} song.Response{response=[]info.BreakerState,degraded=bool}
+// @Failure 500 "something bad with marshal data"
+// @Router /api/enrichment/breakers [get]
+func (bh *BreakerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	states := make([]BreakerState, 0, len(bh.Sources))
+	degraded := false
+	for _, source := range bh.Sources {
+		if source.Breaker == nil {
+			continue
+		}
+		state := source.Breaker.State()
+		if state.State != BreakerClosed {
+			degraded = true
+		}
+		states = append(states, state)
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": states,
+		"degraded": degraded,
+	})
+}
@@ -0,0 +1,99 @@
+package info
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var errReadTimeout = errors.New("response body is not read within read timeout")
+
+// HTTPProvider requests song details from info API: GET /info?group=...&song=... returns {"SongDetail":{...}}
+type HTTPProvider struct {
+	BaseURL string
+	HTTP    *http.Client
+	//ReadTimeout limits reading of response body after headers are received, zero means no limit
+	ReadTimeout time.Duration
+}
+
+// NewHTTPProvider limits time of connection, time of waiting for response headers after request is sent
+// and time of reading response body, each read timeout is counted separately.
+// The whole request is limited by context, zero timeout means no limit
+func NewHTTPProvider(baseURL string, connectTimeout, readTimeout time.Duration) *HTTPProvider {
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = readTimeout
+
+	return &HTTPProvider{
+		BaseURL: baseURL,
+		HTTP: &http.Client{
+			Transport: transport,
+		},
+		ReadTimeout: readTimeout,
+	}
+}
+
+func (c *HTTPProvider) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+
+	params := url.Values{}
+	params.Add("group", group)
+	params.Add("song", songName)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/info?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTP.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status code from info API: [%d]", resp.StatusCode)
+	}
+
+	if c.ReadTimeout > 0 {
+		timer := time.AfterFunc(c.ReadTimeout, func() { cancel(errReadTimeout) })
+		defer timer.Stop()
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if cause := context.Cause(ctx); cause != nil {
+			err = cause
+		}
+		return nil, fmt.Errorf("read body from info API: %w", err)
+	}
+
+	answer := &struct {
+		SongDetail *Detail `json:"SongDetail"`
+	}{}
+	err = json.Unmarshal(body, answer)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal body from info API: %w", err)
+	}
+	if answer.SongDetail == nil {
+		return nil, errors.New("no SongDetail in info API answer")
+	}
+
+	return answer.SongDetail, nil
+}
@@ -0,0 +1,55 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPProviderReadTimeout(t *testing.T) {
+
+	//headers are sent at once, body comes after delay
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay, _ := time.ParseDuration(r.URL.Query().Get("song"))
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"SongDetail":{"link":"some link"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewHTTPProvider(server.URL, time.Second, 100*time.Millisecond)
+
+	tests := []struct {
+		name    string
+		delay   string
+		wantErr error
+	}{
+		{"body within timeout", "10ms", nil},
+		{"slow body", "2s", errReadTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			start := time.Now()
+			detail, err := provider.Fetch(context.Background(), "muse", tt.delay)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("fetch: got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && detail.Link != "some link" {
+				t.Errorf("detail: got %+v", detail)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("fetch took %v, want it limited by read timeout", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package info
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when info API does not know the song, retrying such request is useless
+var ErrNotFound = errors.New("song is not found by info API")
+
+// Detail is song information from info API, empty fields are unknown
+type Detail struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// MetadataProvider looks up song details by group and song names
+type MetadataProvider interface {
+	Fetch(ctx context.Context, group, songName string) (*Detail, error)
+}
+
+// complete reports whether all fields are known
+func (d *Detail) complete() bool {
+	return d.ReleaseDate != "" && d.Text != "" && d.Link != ""
+}
+
+// merge fills empty fields of d from other
+func (d *Detail) merge(other *Detail) {
+	if d.ReleaseDate == "" {
+		d.ReleaseDate = other.ReleaseDate
+	}
+	if d.Text == "" {
+		d.Text = other.Text
+	}
+	if d.Link == "" {
+		d.Link = other.Link
+	}
+}
@@ -0,0 +1,42 @@
+package info
+
+import (
+	"context"
+	"sync"
+)
+
+// fakeProvider answers with detail or err and counts calls, when started is set Fetch reports start
+// to it and waits for release
+type fakeProvider struct {
+	detail  *Detail
+	err     error
+	started chan struct{}
+	release chan struct{}
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *fakeProvider) Fetch(ctx context.Context, group, songName string) (*Detail, error) {
+
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+
+	if p.started != nil {
+		p.started <- struct{}{}
+		<-p.release
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	detail := *p.detail
+	return &detail, nil
+}
+
+func (p *fakeProvider) callsCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
@@ -0,0 +1,69 @@
+package song
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Cursor is position in songs list for keyset pagination. Values are values of sort fields of the last song
+// of previous page or the first song of next page when Backward is true, Sort is the order they were taken for.
+// Clients get it only as opaque string.
+type Cursor struct {
+	Values   []*string `json:"v"`
+	Sort     string    `json:"s"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// Counter is implemented by storages which can count songs of filter, count may be estimated when exact counting
+// is expensive, Limit, Offset and Cursor of filter are ignored
+type Counter interface {
+	CountSongs(filter Filter) (count int, estimated bool, err error)
+}
+
+// NewCursor returns cursor pointing at item in songs list ordered by keys
+func NewCursor(item *Song, keys []SortKey, backward bool) *Cursor {
+
+	values := make([]*string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, SortValue(item, key.Field))
+	}
+
+	return &Cursor{
+		Values:   values,
+		Sort:     FormatSort(keys),
+		Backward: backward,
+	}
+}
+
+func EncodeCursor(cursor *Cursor) string {
+
+	//cursor has only strings and bools, so marshal does not fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads cursor and checks that it was made for songs list ordered by keys
+func DecodeCursor(value string, keys []SortKey) (*Cursor, error) {
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, NewValidationError("cursor", "bad cursor")
+	}
+
+	cursor := &Cursor{}
+	err = json.Unmarshal(data, cursor)
+	if err != nil || len(cursor.Values) != len(keys) {
+		return nil, NewValidationError("cursor", "bad cursor")
+	}
+
+	if cursor.Sort != FormatSort(keys) {
+		return nil, NewValidationError("cursor", "cursor was made for sort [%s]", cursor.Sort)
+	}
+
+	//id is the last sort field and never empty
+	if cursor.Values[len(cursor.Values)-1] == nil {
+		return nil, NewValidationError("cursor", "bad cursor")
+	}
+
+	return cursor, nil
+}
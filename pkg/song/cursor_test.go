@@ -0,0 +1,121 @@
+package song
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseSort(t *testing.T, value string) []SortKey {
+
+	t.Helper()
+
+	keys, err := ParseSort(value)
+	if err != nil {
+		t.Fatalf("parse sort %q error: %v", value, err)
+	}
+	return keys
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+
+	created := time.Date(2024, 3, 1, 10, 20, 30, 123456789, time.FixedZone("MSK", 3*60*60))
+	item := &Song{
+		ID:          42,
+		Name:        "Song, with \"quotes\" and юникод",
+		Group:       "Muse",
+		ReleaseDate: "07.09.2009",
+		CreatedAt:   created,
+		UpdatedAt:   created.Add(time.Hour),
+	}
+	noDate := *item
+	noDate.ReleaseDate = ""
+
+	tests := []struct {
+		name       string
+		item       *Song
+		sort       string
+		backward   bool
+		wantValues []string
+	}{
+		{"default", item, "", false, []string{"42"}},
+		{"backward", item, "-id", true, []string{"42"}},
+		{"multi key", item, "group,-releaseDate,song", false, []string{"Muse", "07.09.2009", item.Name, "42"}},
+		{"times in utc", item, "createdAt,-updatedAt", true, []string{"2024-03-01T07:20:30.123456789Z", "2024-03-01T08:20:30.123456789Z", "42"}},
+		{"without release date", &noDate, "-releaseDate,group", false, []string{"", "Muse", "42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			keys := mustParseSort(t, tt.sort)
+			value := EncodeCursor(NewCursor(tt.item, keys, tt.backward))
+			if strings.ContainsAny(value, "+/=") {
+				t.Errorf("cursor %q is not url safe", value)
+			}
+
+			cursor, err := DecodeCursor(value, keys)
+			if err != nil {
+				t.Fatalf("decode cursor error: %v", err)
+			}
+			if cursor.Backward != tt.backward || cursor.Sort != FormatSort(keys) {
+				t.Errorf("cursor has backward %v and sort %q, want %v and %q", cursor.Backward, cursor.Sort, tt.backward, FormatSort(keys))
+			}
+
+			//empty string stands for nil in want
+			values := make([]string, 0, len(cursor.Values))
+			for i, value := range cursor.Values {
+				if value == nil {
+					values = append(values, "")
+					continue
+				}
+				if *value == "" {
+					t.Errorf("value %d is empty, want nil or value", i)
+				}
+				values = append(values, *value)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("values are %q, want %q", values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestDecodeTamperedCursor(t *testing.T) {
+
+	keys := mustParseSort(t, "group,-releaseDate")
+	valid := EncodeCursor(NewCursor(&Song{ID: 1, Group: "Muse", ReleaseDate: "2009"}, keys, false))
+	encode := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "%%%"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"v":["1"],"s":"id"}`))},
+		{"truncated", valid[:len(valid)-4]},
+		{"not json", encode("group=Muse")},
+		{"values of other type", encode(`{"v":[1,2,3],"s":"group,-releaseDate,id"}`)},
+		{"less values", encode(`{"v":["Muse","1"],"s":"group,-releaseDate,id"}`)},
+		{"more values", encode(`{"v":["Muse",null,"1","2"],"s":"group,-releaseDate,id"}`)},
+		{"other sort", encode(`{"v":["Muse",null,"1"],"s":"group,releaseDate,id"}`)},
+		{"without id", encode(`{"v":["Muse",null,null],"s":"group,-releaseDate,id"}`)},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := DecodeCursor(tt.value, keys)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != "cursor" {
+				t.Errorf("got %v, want validation error of cursor", err)
+			}
+		})
+	}
+}
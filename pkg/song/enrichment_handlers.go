@@ -0,0 +1,79 @@
+package song
+
+import (
+	"net/http"
+)
+
+// @Summary Get enrichment status
+// @Description Get state of background job filling release date, text and link of song from info API
+// @Tags songs
+// @ID get-enrichment
+// @Param id path int true "song id"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Enrichment}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/{id}/enrichment [get]
+func (sh *SongHandler) GetEnrichment(w http.ResponseWriter, r *http.Request) {
+
+	queue, ok := sh.Storage.(EnrichmentQueue)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	enrichment, err := queue.GetEnrichment(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": enrichment,
+	})
+}
+
+// @Summary Trigger enrichment again
+// @Description Reset enrichment job of song to pending with zero attempts, job is processed in background
+// @Tags songs
+// @ID retry-enrichment
+// @Param id path int true "song id"
+// @Produce json
+// @Success 202 {object} song.Response{response=song.Enrichment}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/{id}/enrichment [post]
+func (sh *SongHandler) RetryEnrichment(w http.ResponseWriter, r *http.Request) {
+
+	queue, ok := sh.Storage.(EnrichmentQueue)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	enrichment, err := queue.EnqueueEnrichment(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteResponse(w, r, http.StatusAccepted, Response{
+		"response": enrichment,
+	})
+}
@@ -0,0 +1,81 @@
+package song
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Storage implementations wrap these values, so callers check them with errors.Is
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("already exists")
+	ErrValidation = errors.New("validation failed")
+	// ErrNotSupported is returned by handlers when configured storage has no needed capability
+	ErrNotSupported = errors.New("not supported by configured storage")
+	// ErrPreconditionFailed means that song was changed since client read it, If-Match does not match its ETag
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrPreconditionRequired is returned for change without If-Match when it is required by config
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// ValidationError describes bad input value, errors.Is(err, ErrValidation) is true for it
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// errorStatuses is the only place where storage errors are mapped to http status codes
+var errorStatuses = []struct {
+	target error
+	status int
+}{
+	{ErrValidation, http.StatusBadRequest},
+	{ErrNotFound, http.StatusNotFound},
+	{ErrConflict, http.StatusConflict},
+	{ErrNotSupported, http.StatusNotImplemented},
+	{ErrPreconditionFailed, http.StatusPreconditionFailed},
+	{ErrPreconditionRequired, http.StatusPreconditionRequired},
+}
+
+// WriteError sends error answer with status from errorStatuses, unknown errors are logged and answered with 500
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+
+	for _, item := range errorStatuses {
+		if !errors.Is(err, item.target) {
+			continue
+		}
+
+		dataToSend, err := GetAnswerWithError(err.Error(), r.URL.Path)
+		if err != nil {
+			log.Printf("marshal answer with error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(item.status)
+		w.Write(dataToSend)
+		return
+	}
+
+	log.Printf("internal error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
+	w.WriteHeader(http.StatusInternalServerError)
+}
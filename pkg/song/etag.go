@@ -0,0 +1,107 @@
+package song
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Versioner is implemented by storages which keep version of song, version grows with every change of song
+type Versioner interface {
+	//IfVersion returns storage which changes and deletes song only when its stored version equals version,
+	//otherwise ErrPreconditionFailed is returned, it shares connection pool with storage
+	IfVersion(version int) Storage
+}
+
+// ETag returns strong entity tag of song version
+func ETag(version int) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// SetVersion sets version of song and its ETag
+func (s *Song) SetVersion(version int) {
+	s.Version = version
+	s.ETag = ETag(version)
+}
+
+// matchETag reports whether header value of If-Match or If-None-Match contains etag,
+// "*" matches any etag, weak tags match only with weak comparison
+func matchETag(header, etag string, weak bool) bool {
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// songStorage returns storage for change of song with id, it records actor of request like actorStorage
+// and changes song only when If-Match header matches its current ETag
+func (sh *SongHandler) songStorage(r *http.Request, id int) (Storage, error) {
+
+	storage, _, err := sh.checkedSongStorage(r, id)
+	return storage, err
+}
+
+// checkedSongStorage is songStorage which also returns song checked against If-Match header,
+// the song is nil when header is missing or is "*"
+func (sh *SongHandler) checkedSongStorage(r *http.Request, id int) (Storage, *Song, error) {
+
+	storage, err := sh.actorStorage(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		if sh.RequireIfMatch {
+			return nil, nil, fmt.Errorf("If-Match header with ETag of song is required: %w", ErrPreconditionRequired)
+		}
+		return storage, nil, nil
+	}
+
+	versioner, ok := storage.(Versioner)
+	if !ok {
+		return nil, nil, fmt.Errorf("If-Match header: %w", ErrNotSupported)
+	}
+
+	//existence of song is checked by storage on change
+	if strings.TrimSpace(header) == "*" {
+		return storage, nil, nil
+	}
+
+	item, err := sh.Storage.GetSong(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !matchETag(header, item.ETag, false) {
+		return nil, nil, fmt.Errorf("song with id [%d] has ETag %s: %w", id, item.ETag, ErrPreconditionFailed)
+	}
+
+	//song may be changed after GetSong, so storage checks version again in its transaction
+	return versioner.IfVersion(item.Version), item, nil
+}
+
+// notModified answers with 304 when If-None-Match header matches etag of song
+func notModified(w http.ResponseWriter, r *http.Request, item *Song) bool {
+
+	header := r.Header.Get("If-None-Match")
+	if header == "" || !matchETag(header, item.ETag, true) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
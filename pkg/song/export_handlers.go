@@ -0,0 +1,225 @@
+package song
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	ExportJSON  = "json"
+	ExportJSONL = "jsonl"
+	ExportCSV   = "csv"
+
+	//ExportErrorTrailer is trailer with error which stopped export after the answer was started
+	ExportErrorTrailer = "X-Export-Error"
+
+	exportBatchSize = 500
+)
+
+// exportContentTypes are Content-Type values of export formats
+var exportContentTypes = map[string]string{
+	ExportJSON:  "application/json",
+	ExportJSONL: "application/x-ndjson",
+	ExportCSV:   "text/csv; charset=utf-8",
+}
+
+// songWriter writes songs in one of export formats with buffering, Flush sends buffered data,
+// End writes the end of format and flushes
+type songWriter interface {
+	Begin() error
+	Write(item *Song) error
+	Flush() error
+	End() error
+}
+
+// @Summary Export songs
+// @Description Export songs as JSON array, JSON Lines or CSV with header id,song,group,releaseDate,text,link.
+// @Description Songs are read from storage by pages ordered by id and written as they are read, so export of the whole library
+// @Description does not need memory for all songs. Filters are the same as in songs list. When reading of songs fails after
+// @Description the answer is started, export is stopped, error is sent in X-Export-Error trailer and JSON array stays unclosed.
+// @Tags songs
+// @ID export-songs
+// @Param format query string false "json, jsonl or csv" Default(json)
+// @Param song query string false "Song"
+// @Param group query string false "Group"
+// @Param releaseDate query string false "Release date (year)"
+// @Param releasedFrom query string false "songs released on this day or later: day.month.year, month.year, year or decade like 1990s"
+// @Param releasedTo query string false "songs released on this day or earlier: day.month.year, month.year, year or decade like 1990s"
+// @Param text query string false "Text"
+// @Param link query boolean false "Link"
+// @Param album query int false "album id"
+// @Param tags query string false "comma separated tag names"
+// @Param tagsMode query string false "and or or" Default(and)
+// @Param q query string false "query with field predicates, ranges, quoted phrases, negation and OR, see songs list"
+// @Param sort query string false "comma separated fields with - for descending order: song, group, releaseDate, id, createdAt, updatedAt" Default(id)
+// @Produce json
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Success 200 {array} song.Song
+// @Header 200 {string} X-Export-Error "trailer, error which stopped export, empty when all songs are written"
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/export [get]
+func (sh *SongHandler) Export(w http.ResponseWriter, r *http.Request) {
+
+	format := r.FormValue("format")
+	if format == "" {
+		format = ExportJSON
+	}
+	if _, ok := exportContentTypes[format]; !ok {
+		WriteError(w, r, NewValidationError("format", "must be json, jsonl or csv"))
+		return
+	}
+
+	filter, err := readFilter(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	filter.Limit = exportBatchSize
+
+	//the first page is read before answer is started, so bad filters get usual error answer
+	songs, err := sh.Storage.GetAll(filter)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", `attachment; filename="songs.`+format+`"`)
+	w.Header().Set("Trailer", ExportErrorTrailer)
+	w.WriteHeader(http.StatusOK)
+
+	writer := newSongWriter(w, format)
+	flusher, _ := w.(http.Flusher)
+
+	count := 0
+	err = writer.Begin()
+	for err == nil {
+		for _, item := range songs {
+			err = writer.Write(item)
+			if err != nil {
+				break
+			}
+		}
+		count += len(songs)
+		if err != nil || len(songs) < exportBatchSize {
+			break
+		}
+
+		err = writer.Flush()
+		if err != nil {
+			break
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		filter.Cursor = NewCursor(songs[len(songs)-1], filter.SortKeys(), false)
+		songs, err = sh.Storage.GetAll(filter)
+	}
+	if err == nil {
+		err = writer.End()
+	}
+
+	//status is already sent, so broken export is reported by trailer, json array stays unclosed
+	if err != nil {
+		w.Header().Set(ExportErrorTrailer, err.Error())
+		log.Printf("export error: [%s], songs written: [%d], user agent: [%s], path: [%s], method: [%s]\n", err.Error(), count, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+		return
+	}
+
+	log.Printf("export done, songs: [%d], format: [%s], user agent: [%s], path: [%s], method: [%s]\n", count, format, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+func newSongWriter(w io.Writer, format string) songWriter {
+
+	switch format {
+	case ExportJSONL:
+		return &jsonSongWriter{w: bufio.NewWriter(w), lines: true}
+	case ExportCSV:
+		return &csvSongWriter{w: csv.NewWriter(w)}
+	}
+	return &jsonSongWriter{w: bufio.NewWriter(w)}
+}
+
+// jsonSongWriter writes JSON array or JSON Lines when lines is true
+type jsonSongWriter struct {
+	w       *bufio.Writer
+	lines   bool
+	written bool
+}
+
+func (jw *jsonSongWriter) Begin() error {
+	if jw.lines {
+		return nil
+	}
+	_, err := jw.w.WriteString("[")
+	return err
+}
+
+func (jw *jsonSongWriter) Write(item *Song) error {
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if !jw.lines && jw.written {
+		err = jw.w.WriteByte(',')
+		if err != nil {
+			return err
+		}
+	}
+	jw.written = true
+
+	_, err = jw.w.Write(data)
+	if err != nil {
+		return err
+	}
+	if jw.lines {
+		return jw.w.WriteByte('\n')
+	}
+	return nil
+}
+
+func (jw *jsonSongWriter) Flush() error {
+	return jw.w.Flush()
+}
+
+func (jw *jsonSongWriter) End() error {
+	if !jw.lines {
+		_, err := jw.w.WriteString("]")
+		if err != nil {
+			return err
+		}
+	}
+	return jw.w.Flush()
+}
+
+type csvSongWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvSongWriter) Begin() error {
+	return cw.w.Write([]string{"id", "song", "group", "releaseDate", "text", "link"})
+}
+
+func (cw *csvSongWriter) Write(item *Song) error {
+	return cw.w.Write([]string{strconv.Itoa(item.ID), item.Name, item.Group, item.ReleaseDate, item.Text, item.Link})
+}
+
+func (cw *csvSongWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvSongWriter) End() error {
+	return cw.Flush()
+}
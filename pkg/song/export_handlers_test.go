@@ -0,0 +1,179 @@
+package song_test
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// failingPages fails to read every page of songs after the first one
+type failingPages struct {
+	song.Storage
+}
+
+func (fp *failingPages) GetAll(filter song.Filter) ([]*song.Song, error) {
+
+	if filter.Cursor != nil {
+		return nil, errors.New("db is down")
+	}
+	return fp.Storage.GetAll(filter)
+}
+
+// newExportServer serves export of count songs, songs are added in order of their names
+func newExportServer(t *testing.T, count int, failing bool) *httptest.Server {
+
+	t.Helper()
+
+	memory := storage.NewMemoryStorage()
+	for i := 1; i <= count; i++ {
+		_, err := memory.Add(fmt.Sprintf("Song %04d", i), "Group", "", "", "")
+		if err != nil {
+			t.Fatalf("add error: %v", err)
+		}
+	}
+
+	var st song.Storage = memory
+	if failing {
+		st = &failingPages{Storage: memory}
+	}
+	server := httptest.NewServer(http.HandlerFunc((&song.SongHandler{Storage: st}).Export))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readExport returns ids of exported songs, body of answer must be read to the end before trailer is used
+func readExport(t *testing.T, format string, body io.Reader) ([]int, error) {
+
+	t.Helper()
+
+	ids := make([]int, 0)
+	switch format {
+	case song.ExportJSON:
+		songs := make([]*song.Song, 0)
+		err := json.NewDecoder(body).Decode(&songs)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range songs {
+			ids = append(ids, item.ID)
+		}
+
+	case song.ExportJSONL:
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			item := &song.Song{}
+			err := json.Unmarshal(scanner.Bytes(), item)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, item.ID)
+		}
+
+	case song.ExportCSV:
+		records, err := csv.NewReader(body).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records[1:] {
+			id, err := strconv.Atoi(record[0])
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// TestExportPages checks that export reads all pages and writes every song once in order of ids
+func TestExportPages(t *testing.T) {
+
+	count := 2*song.ExportBatchSize + 1
+	server := newExportServer(t, count, false)
+
+	for _, format := range []string{song.ExportJSON, song.ExportJSONL, song.ExportCSV} {
+		t.Run(format, func(t *testing.T) {
+
+			resp, err := http.Get(server.URL + "/api/songs/export?sort=song&format=" + format)
+			if err != nil {
+				t.Fatalf("get error: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status is %d, want 200", resp.StatusCode)
+			}
+
+			ids, err := readExport(t, format, resp.Body)
+			if err != nil {
+				t.Fatalf("read export error: %v", err)
+			}
+			io.Copy(io.Discard, resp.Body)
+
+			if len(ids) != count {
+				t.Fatalf("got %d songs, want %d", len(ids), count)
+			}
+			for i, id := range ids {
+				if id != i+1 {
+					t.Fatalf("song %d has id %d, want %d", i, id, i+1)
+				}
+			}
+			if message := resp.Trailer.Get(song.ExportErrorTrailer); message != "" {
+				t.Errorf("trailer %s is [%s], want empty", song.ExportErrorTrailer, message)
+			}
+		})
+	}
+}
+
+// TestExportPageError checks that error of the next page is sent in trailer after songs of the first page
+func TestExportPageError(t *testing.T) {
+
+	server := newExportServer(t, song.ExportBatchSize+1, true)
+
+	for _, format := range []string{song.ExportJSON, song.ExportJSONL, song.ExportCSV} {
+		t.Run(format, func(t *testing.T) {
+
+			resp, err := http.Get(server.URL + "/api/songs/export?format=" + format)
+			if err != nil {
+				t.Fatalf("get error: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status is %d, want 200", resp.StatusCode)
+			}
+
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body error: %v", err)
+			}
+
+			message := resp.Trailer.Get(song.ExportErrorTrailer)
+			if !strings.Contains(message, "db is down") {
+				t.Errorf("trailer %s is [%s], want error of storage", song.ExportErrorTrailer, message)
+			}
+
+			ids, err := readExport(t, format, strings.NewReader(string(data)))
+			if format == song.ExportJSON {
+				if err == nil {
+					t.Errorf("json array of broken export is closed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read export error: %v", err)
+			}
+			if len(ids) != song.ExportBatchSize {
+				t.Errorf("got %d songs, want songs of the first page %d", len(ids), song.ExportBatchSize)
+			}
+		})
+	}
+}
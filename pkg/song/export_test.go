@@ -0,0 +1,4 @@
+package song
+
+// ExportBatchSize is size of export page for tests of package song_test
+const ExportBatchSize = exportBatchSize
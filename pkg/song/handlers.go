@@ -5,26 +5,38 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // @Summary Get songs list with pagination and filtering by all fields
-// @Description Get songs list with pagination and filtering by all fields
+// @Description Get songs list with pagination and filtering by all fields. With limit answer has nextCursor and prevCursor for keyset pagination
+// @Description and total number of songs of filter, total is estimated for large postgres tables
 // @Tags songs
 // @ID get-all
+// @Deprecated
 // @Param limit query int false "Limit" Default(0)
 // @Param offset query int false "Offset" Default(0)
+// @Param cursor query string false "nextCursor or prevCursor of previous answer, not used with offset"
 // @Param song query string false "song name"
 // @Param group query string false "group name"
 // @Param releaseDate query string false "year"
+// @Param releasedFrom query string false "songs released on this day or later, format: day.month.year, month.year, year or decade like 1990s"
+// @Param releasedTo query string false "songs released on this day or earlier, format: day.month.year, month.year, year or decade like 1990s"
 // @Param text query string false "text, word, letters"
 // @Param link query string false "if need song with video use: true, else use:false"
+// @Param album query int false "album id, songs from album tracklist (postgres only)"
+// @Param tags query string false "comma separated tag or genre names, case insensitive (postgres only)"
+// @Param tagsMode query string false "and: songs with all tags, or: songs with any of tags" Default(and)
+// @Param sort query string false "comma separated fields with - for descending order: song, group, releaseDate, id, createdAt, updatedAt, e.g. -releaseDate,group,song" Default(id)
+// @Param q query string false "query with field predicates song, group, text, link, year, released, ranges, quoted phrases, negation and OR, e.g. group:muse year:2006..2010 -link:false"
+// @Param fuzzy query string false "typo-tolerant search by song and group names ordered by similarity, use: true (postgres only)"
 // @Produce json
-// @Success 200 {object} song.Response{response=[]song.Song}
+// @Success 200 {object} song.Response{response=[]song.Song,didYouMean=song.Suggestion,nextCursor=string,prevCursor=string,total=int,totalEstimated=bool}
 // @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
 // @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
 // @Router /api/songs [get]
 func (sh *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
@@ -75,51 +87,100 @@ func (sh *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	link := r.FormValue("link")
-	if link != "false" && link != "true" && link != "" {
-		dataToSend, err := GetAnswerWithError("link must be true or false", r.URL.Path)
+	fuzzy := r.FormValue("fuzzy")
+	if fuzzy != "false" && fuzzy != "true" && fuzzy != "" {
+		WriteError(w, r, NewValidationError("fuzzy", "must be true or false"))
+		return
+	}
+
+	filter, err := readFilter(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	//fuzzy search orders songs by similarity
+	if r.FormValue("sort") != "" && fuzzy == "true" {
+		WriteError(w, r, NewValidationError("sort", "cannot be used with fuzzy search"))
+		return
+	}
+
+	if r.FormValue("cursor") != "" {
+		if offset > 0 || fuzzy == "true" {
+			WriteError(w, r, NewValidationError("cursor", "cannot be used with offset or fuzzy search"))
+			return
+		}
+		filter.Cursor, err = DecodeCursor(r.FormValue("cursor"), filter.SortKeys())
 		if err != nil {
-			log.Printf("marshal answer bad link error [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-			w.WriteHeader(http.StatusInternalServerError)
+			WriteError(w, r, err)
 			return
 		}
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write(dataToSend)
-		return
 	}
 
-	//get songs
-	songs, err := sh.Storage.GetAll(
-		limit,
-		offset,
-		r.FormValue("song"),
-		r.FormValue("group"),
-		r.FormValue("releaseDate"),
-		r.FormValue("text"),
-		r.FormValue("link"),
-	)
+	finder, isFuzzyFinder := sh.Storage.(FuzzyFinder)
 
-	if err != nil {
-		if err.Error() == sh.Storage.GetErrorBadDate().Error() {
-			dataToSend, err := GetAnswerWithError("bad date, date must have format year", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer with get all songs error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
+	var response Response
+	if fuzzy == "true" {
+		if !isFuzzyFinder {
+			WriteError(w, r, ErrNotSupported)
 			return
 		}
 
-		log.Printf("get all song text error: [%s], data: [limit: %d, offset: %d, song: %s, group: %s, releaseDate: %s, text: %s, link: %s]\n",
-			err.Error(), limit, offset, r.FormValue("song"), r.FormValue("group"), r.FormValue("releaseDate"), r.FormValue("text"), r.FormValue("link"))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
+		//get songs ordered by similarity of song and group names
+		songs, err := finder.FindSimilar(filter, sh.FuzzyThreshold)
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
 
-	response := Response{
-		"response": songs,
+		response = Response{
+			"response": songs,
+		}
+	} else {
+		//get songs, one more song than limit tells whether there is next page
+		if limit > 0 {
+			filter.Limit = limit + 1
+		}
+		songs, err := sh.Storage.GetAll(filter)
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+
+		response = Response{}
+		if limit > 0 {
+			var next, prev *Cursor
+			songs, next, prev = cutPage(songs, filter, limit)
+			if next != nil {
+				response["nextCursor"] = EncodeCursor(next)
+			}
+			if prev != nil {
+				response["prevCursor"] = EncodeCursor(prev)
+			}
+
+			if counter, ok := sh.Storage.(Counter); ok {
+				total, estimated, err := counter.CountSongs(filter)
+				if err != nil {
+					WriteError(w, r, err)
+					return
+				}
+				response["total"] = total
+				response["totalEstimated"] = estimated
+			}
+		}
+		response["response"] = songs
+
+		//suggest closest names when exact search by names found nothing
+		if len(songs) == 0 && isFuzzyFinder && (filter.SongName != "" || filter.Group != "") {
+			suggestion, err := finder.Suggest(filter.SongName, filter.Group, sh.FuzzyThreshold)
+			if err != nil {
+				log.Printf("get suggestion error: [%s], data: [song: %s, group: %s]\n", err.Error(), filter.SongName, filter.Group)
+			} else if suggestion != nil {
+				response["didYouMean"] = suggestion
+			}
+		}
 	}
 
 	dataResponse, err := json.Marshal(response)
@@ -137,16 +198,131 @@ func (sh *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// readFilter reads filter fields of songs list except pagination, they are shared by list and export
+func readFilter(r *http.Request) (Filter, error) {
+
+	link := r.FormValue("link")
+	if link != "false" && link != "true" && link != "" {
+		return Filter{}, NewValidationError("link", "must be true or false")
+	}
+
+	var albumID int
+	if r.FormValue("album") != "" {
+		var err error
+		albumID, err = strconv.Atoi(r.FormValue("album"))
+		if err != nil || albumID <= 0 {
+			return Filter{}, NewValidationError("album", "must be positive number")
+		}
+	}
+
+	var tags []string
+	for _, name := range strings.Split(r.FormValue("tags"), ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			tags = append(tags, name)
+		}
+	}
+
+	tagsMode := r.FormValue("tagsMode")
+	if tagsMode != "and" && tagsMode != "or" && tagsMode != "" {
+		return Filter{}, NewValidationError("tagsMode", `must be "and" or "or"`)
+	}
+
+	sort, err := ParseSort(r.FormValue("sort"))
+	if err != nil {
+		return Filter{}, err
+	}
+
+	var query *Query
+	if strings.TrimSpace(r.FormValue("q")) != "" {
+		query, err = ParseQuery(r.FormValue("q"))
+		if err != nil {
+			return Filter{}, err
+		}
+	}
+
+	//song released within releasedTo period is included
+	var releasedFrom, releasedBefore time.Time
+	if r.FormValue("releasedFrom") != "" {
+		releasedFrom, _, err = ParseReleasePeriod("releasedFrom", r.FormValue("releasedFrom"))
+		if err != nil {
+			return Filter{}, err
+		}
+	}
+	if r.FormValue("releasedTo") != "" {
+		_, releasedBefore, err = ParseReleasePeriod("releasedTo", r.FormValue("releasedTo"))
+		if err != nil {
+			return Filter{}, err
+		}
+		if !releasedFrom.Before(releasedBefore) {
+			return Filter{}, NewValidationError("releasedTo", "must not be before releasedFrom")
+		}
+	}
+
+	return Filter{
+		SongName:       r.FormValue("song"),
+		Group:          r.FormValue("group"),
+		Year:           r.FormValue("releaseDate"),
+		Text:           r.FormValue("text"),
+		Link:           link,
+		AlbumID:        albumID,
+		Tags:           tags,
+		AnyTag:         tagsMode == "or",
+		Sort:           sort,
+		ReleasedFrom:   releasedFrom,
+		ReleasedBefore: releasedBefore,
+		Query:          query,
+	}, nil
+}
+
+// cutPage drops the extra song read after limit and returns cursors of next and previous pages,
+// filter is filter of the read page
+func cutPage(songs []*Song, filter Filter, limit int) ([]*Song, *Cursor, *Cursor) {
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	more := len(songs) > limit
+	if more && backward {
+		songs = songs[len(songs)-limit:]
+	} else if more {
+		songs = songs[:limit]
+	}
+
+	if len(songs) == 0 {
+		return songs, nil, nil
+	}
+
+	//backward page was requested from the page after it, forward page from the page before it
+	hasNext, hasPrev := more, filter.Cursor != nil || filter.Offset > 0
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	var next, prev *Cursor
+	if hasNext {
+		next = NewCursor(songs[len(songs)-1], filter.SortKeys(), false)
+	}
+	if hasPrev {
+		prev = NewCursor(songs[0], filter.SortKeys(), true)
+	}
+
+	return songs, next, prev
+}
+
 // @Summary Get song text with verse pagination
-// @Description Get song text with verse pagination
+// @Description Get song text with verse pagination, verses are stored parsed on write, items contain position and kind of every verse
 // @Tags songs
 // @ID get
+// @Deprecated
 // @Param limit query int false "Limit" Default(0)
 // @Param offset query int false "Offset" Default(0)
 // @Param id path int true "song id"
+// @Param If-None-Match header string false "ETag of song from previous answer, 304 is sent when song is not changed"
 // @Produce json
-// @Success 200 {object} song.Response{response=song.Response{id=int,verses=string,resesInSong=int}}
+// @Success 200 {object} song.Response{response=song.Response{id=int,verses=string,versesInSong=int,items=[]song.Verse}}
+// @Header 200 {string} ETag "version of song"
+// @Success 304 "song is not changed"
 // @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
 // @Failure 500 "something bad with db or marshal/unmarshal data"
 // @Router /api/songs/{id} [get]
 func (sh *SongHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -213,42 +389,35 @@ func (sh *SongHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//get text of song
-	song.Text, err = sh.Storage.Get(song.ID)
+	//ETag is version of the whole song, so unchanged song is not sent again for any page of verses
+	stored, err := sh.Storage.GetSong(song.ID)
 	if err != nil {
-		if err.Error() == sh.Storage.GetErrorNoRows().Error() {
-			dataToSend, err := GetAnswerWithError("no song with this id", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer get song text error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
-			return
-		}
-		log.Printf("get song text error: [%s], data: [id: %d]\n", err.Error(), song.ID)
-		w.WriteHeader(http.StatusInternalServerError)
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", stored.ETag)
+	if notModified(w, r, stored) {
 		return
 	}
-	verses := strings.Split(song.Text, "\n\n")
 
 	//get desired verses of the song text
-	var desiredVerses []string
-	switch {
-	case offset == 0 && limit == 0:
-		desiredVerses = verses
-	case offset+limit <= len(verses):
-		desiredVerses = verses[offset : offset+limit]
-	case offset < len(verses):
-		desiredVerses = verses[offset:]
+	verses, versesInSong, err := sh.Storage.GetVerses(song.ID, limit, offset)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	desiredVerses := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		desiredVerses = append(desiredVerses, verse.Text)
 	}
 
 	response := Response{
 		"response": Response{
 			"id":           song.ID,
 			"verses":       strings.Join(desiredVerses, "\n\n"),
-			"versesInSong": len(verses),
+			"versesInSong": versesInSong,
+			"items":        verses,
 		},
 	}
 
@@ -268,16 +437,18 @@ func (sh *SongHandler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Add new song to library
-// @Description Add new song to library
+// @Description Add new song to library, release date, text and link are requested from info API in background, see enrichment status
 // @Tags songs
 // @ID new
+// @Deprecated
 // @Param bodyJSON body string true "song and group names" SchemaExample({"song":"sone song name","group":"some group name"})
+// @Param X-Actor header string false "who changes song, recorded in revision" Default(anonymous)
 // @Accept json
 // @Produce json
-// @Success 201 {object} song.Response{response=song.Response{id=int}}
+// @Success 201 {object} song.Response{response=song.Response{id=int,enrichment=string}}
 // @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
 // @Failure 500 "something bad with db or marshal/unmarshal data"
-// @Failure 502 "something bad with another server for getting song info"
 // @Router /api/songs [put]
 func (sh *SongHandler) New(w http.ResponseWriter, r *http.Request) {
 
@@ -317,85 +488,32 @@ func (sh *SongHandler) New(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//prepare to send request to another server
-	searcherParams := url.Values{}
-	searcherParams.Add("group", song.Group)
-	searcherParams.Add("song", song.Name)
-
-	newRequest, err := http.NewRequest("GET", sh.ExternalAPI+"/info"+"?"+searcherParams.Encode(), nil)
+	storage, err := sh.actorStorage(r)
 	if err != nil {
-		log.Printf("creating new request error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(newRequest)
-	if err != nil {
-		log.Printf("getting response error from server [%s]: error [%s], path: [%s]\n", sh.ExternalAPI, err.Error(), newRequest.URL.Path)
-		w.WriteHeader(http.StatusBadGateway)
-		return
-	}
-	if resp.StatusCode != 200 {
-		log.Printf("bad status code from server [%s]: code [%d], path: [%s]\n", sh.ExternalAPI, resp.StatusCode, newRequest.URL.Path)
-		w.WriteHeader(http.StatusBadGateway)
+		WriteError(w, r, err)
 		return
 	}
 
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("request body read error from server [%s]: error [%s], path: [%s], method: [%s]\n", sh.ExternalAPI, err.Error(), newRequest.URL.Path, newRequest.Method)
-
-		dataToSend, err := GetAnswerWithError("request body read error from server", sh.ExternalAPI)
-		if err != nil {
-			log.Printf("marshal answer body read error from server [%s]: error: [%s], path: [%s], method: [%s]\n", sh.ExternalAPI, err.Error(), r.URL.Path, r.Method)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+	//add new song to storage, release date, text and link are filled by enrichment job added with song
+	enrichmentStatus := ""
+	if queue, ok := storage.(EnrichmentQueue); ok {
+		var enrichment *Enrichment
+		song.ID, enrichment, err = queue.AddWithEnrichment(song.Name, song.Group, "", "", "")
+		if err == nil {
+			enrichmentStatus = enrichment.Status
 		}
-		w.WriteHeader(http.StatusBadGateway)
-		w.Write(dataToSend)
-		return
-	}
-
-	m := make(map[string](map[string]string))
-
-	err = json.Unmarshal(respBody, &m)
-	if err != nil {
-		log.Printf("unmarshal body error from server [%s]: error [%s], path: [%s], method: [%s]\n", sh.ExternalAPI, err.Error(), newRequest.URL.Path, newRequest.Method)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	} else {
+		song.ID, err = storage.Add(song.Name, song.Group, "", "", "")
 	}
-
-	song.ReleaseDate = m["SongDetail"]["releaseDate"]
-	song.Text = m["SongDetail"]["text"]
-	song.Link = m["SongDetail"]["link"]
-
-	//add new song to storage
-	song.ID, err = sh.Storage.Add(song.Name, song.Group, song.ReleaseDate, song.Text, song.Link)
-
 	if err != nil {
-		if err.Error() == sh.Storage.GetErrorAlreadyExist().Error() {
-			dataToSend, err := GetAnswerWithError("song of this group is already added", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer add song error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
-			return
-		}
-
-		log.Printf("add new song error: [%s], data: [song: %s, group: %s, date: %s, link: %s]\n", err.Error(), song.Name, song.Group, song.ReleaseDate, song.Link)
-		w.WriteHeader(http.StatusInternalServerError)
+		WriteError(w, r, err)
 		return
 	}
 
 	response := Response{
 		"response": Response{
-			"id": song.ID,
+			"id":         song.ID,
+			"enrichment": enrichmentStatus,
 		},
 	}
 
@@ -419,9 +537,15 @@ func (sh *SongHandler) New(w http.ResponseWriter, r *http.Request) {
 // @Description Update song data
 // @Tags songs
 // @ID update
+// @Deprecated
 // @Param bodyJSON body string true "song id and at least one of the listed parameters required" SchemaExample({"id":2,"releaseDate":"25.02.2012","text":"some text","link":"some link"})
+// @Param X-Actor header string false "who changes song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is changed only when it is not changed by others since read"
 // @Success 200
 // @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
 // @Failure 500 "something bad with db or marshal/unmarshal data"
 // @Router /api/songs [post]
 func (sh *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -450,47 +574,16 @@ func (sh *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//update song data (link and/or release date and/or text)
-	err = sh.Storage.Update(song.ID, song.ReleaseDate, song.Text, song.Link)
+	storage, err := sh.songStorage(r, song.ID)
 	if err != nil {
-		if err.Error() == sh.Storage.GetErrorNoUpdate().Error() {
-			dataToSend, err := GetAnswerWithError("no data to update, release date or link or text must be not empty", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer with update song error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
-			return
-		}
-
-		if err.Error() == sh.Storage.GetErrorBadID().Error() {
-			dataToSend, err := GetAnswerWithError("bad id, nothing updated", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer with update song error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
-			return
-		}
-
-		if err.Error() == sh.Storage.GetErrorBadDate().Error() {
-			dataToSend, err := GetAnswerWithError("bad date, date must have format day.month.year", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer with update song error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
 
-		log.Printf("update song error: [%s], data: [id: %d, text: %s, date: %s, link: %s]\n", err.Error(), song.ID, song.Text, song.ReleaseDate, song.Link)
-		w.WriteHeader(http.StatusInternalServerError)
+	//update song data (link and/or release date and/or text)
+	err = storage.Update(song.ID, song.ReleaseDate, song.Text, song.Link)
+	if err != nil {
+		WriteError(w, r, err)
 		return
 	}
 
@@ -499,12 +592,18 @@ func (sh *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete song
-// @Description Delete song
+// @Description Move song to trash, it can be restored until it is purged. Songs in trash are deleted permanently after retention period
 // @Tags songs
 // @ID delete
+// @Deprecated
 // @Param bodyJSON body string true "song id" SchemaExample({"id":2})
+// @Param X-Actor header string false "who changes song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is changed only when it is not changed by others since read"
 // @Success 200
 // @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
 // @Failure 500 "something bad with db or marshal/unmarshal data"
 // @Router /api/songs [delete]
 func (sh *SongHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -533,26 +632,77 @@ func (sh *SongHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//delete song from storage
-	err = sh.Storage.Delete(song.ID)
+	storage, err := sh.songStorage(r, song.ID)
 	if err != nil {
-		if err.Error() == sh.Storage.GetErrorBadID().Error() {
-			dataToSend, err := GetAnswerWithError("bad id, nothing deleted", r.URL.Path)
-			if err != nil {
-				log.Printf("marshal answer with delete song error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(dataToSend)
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
 
-		log.Printf("delete song error: [%s] [id: %d]\n", err.Error(), song.ID)
-		w.WriteHeader(http.StatusInternalServerError)
+	//delete song from storage
+	err = storage.Delete(song.ID)
+	if err != nil {
+		WriteError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 	log.Printf("song deleted, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", song.ID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
 }
+
+// @Summary Full-text search over lyrics
+// @Description Search songs by words in lyrics, song and group names with language-aware stemming, results are ranked by relevance and contain the best matching verse with highlighted words. Available only with postgres storage
+// @Tags songs
+// @ID search
+// @Param q query string true "search query, supports quoted phrases, OR and -word"
+// @Param lang query string false "text search language: simple, english, russian" Default(english)
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Produce json
+// @Success 200 {object} song.Response{response=[]song.SearchResult}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/search [get]
+func (sh *SongHandler) Search(w http.ResponseWriter, r *http.Request) {
+
+	searcher, ok := sh.Storage.(Searcher)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	limit, offset, err := ReadPagination(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	language := r.FormValue("lang")
+	if language == "" {
+		language = "english"
+	}
+
+	results, err := searcher.Search(r.FormValue("q"), language, limit, offset)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	response := Response{
+		"response": results,
+	}
+
+	dataResponse, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("marshal response with search results error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, err = w.Write(dataResponse)
+	if err != nil {
+		log.Printf("sending response error: [%s], user agent: [%s], path: [%s], method: [%s]\n", err.Error(), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	} else {
+		log.Printf("response sended: [%s], user agent: [%s], path: [%s], method: [%s]\n", string(dataResponse), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	}
+}
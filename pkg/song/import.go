@@ -0,0 +1,204 @@
+package song
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	ImportCSV   = "csv"
+	ImportJSONL = "jsonl"
+
+	//duplicates by song and group names are reported as failed rows, skipped or updated
+	DuplicateFail   = "fail"
+	DuplicateSkip   = "skip"
+	DuplicateUpdate = "update"
+
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportResult model info
+// @Description result of importing one row, line is line number of row in imported file
+type ImportResult struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Song   string `json:"song,omitempty"`
+	Group  string `json:"group,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Importer is implemented by storages with bulk import, every call of ImportSongs is one transaction
+type Importer interface {
+	//ImportSongs adds songs and returns results in the same order, errors of single songs are reported in results,
+	//returned error means that the whole batch is not saved
+	ImportSongs(songs []*Song, onDuplicate string) ([]*ImportResult, error)
+}
+
+// ImportReader reads songs one by one from CSV with header or from JSON Lines, fields are named like in Song json
+type ImportReader struct {
+	format  string
+	csv     *csv.Reader
+	columns map[string]int
+	lines   *bufio.Reader
+	line    int
+}
+
+// importColumns are fields of Song which can be imported
+var importColumns = []string{"song", "group", "releaseDate", "text", "link"}
+
+func IsImportFormat(format string) bool {
+	return format == ImportCSV || format == ImportJSONL
+}
+
+func IsDuplicateMode(mode string) bool {
+	return mode == DuplicateFail || mode == DuplicateSkip || mode == DuplicateUpdate
+}
+
+// NewImportReader reads header of CSV, header must have song and group columns
+func NewImportReader(r io.Reader, format string) (*ImportReader, error) {
+
+	ir := &ImportReader{
+		format: format,
+	}
+
+	switch format {
+	case ImportJSONL:
+		ir.lines = bufio.NewReader(r)
+		return ir, nil
+	case ImportCSV:
+	default:
+		return nil, NewValidationError("format", "must be csv or jsonl")
+	}
+
+	ir.csv = csv.NewReader(r)
+	ir.csv.FieldsPerRecord = -1
+	header, err := ir.csv.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, NewValidationError("body", "csv header is missing")
+		}
+		return nil, NewValidationError("body", "bad csv header: %s", err.Error())
+	}
+
+	ir.columns = make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		column := ""
+		for _, item := range importColumns {
+			if strings.EqualFold(name, item) {
+				column = item
+			}
+		}
+		if column == "" {
+			return nil, NewValidationError("body", "unknown csv column [%s], columns are %s", name, strings.Join(importColumns, ", "))
+		}
+		ir.columns[column] = i
+	}
+
+	if _, ok := ir.columns["song"]; !ok {
+		return nil, NewValidationError("body", "csv column song is missing")
+	}
+	if _, ok := ir.columns["group"]; !ok {
+		return nil, NewValidationError("body", "csv column group is missing")
+	}
+
+	return ir, nil
+}
+
+// Next returns the next song with its line number, io.EOF means end of input,
+// validation error is error of this row only, reading can be continued after it
+func (ir *ImportReader) Next() (int, *Song, error) {
+
+	if ir.format == ImportCSV {
+		return ir.nextCSV()
+	}
+	return ir.nextJSONL()
+}
+
+func (ir *ImportReader) nextCSV() (int, *Song, error) {
+
+	record, err := ir.csv.Read()
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.StartLine, nil, NewValidationError("", "bad csv row: %s", parseErr.Err.Error())
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	line, _ := ir.csv.FieldPos(0)
+
+	value := func(column string) string {
+		i, ok := ir.columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	//text keeps its spaces and line breaks
+	item := &Song{
+		Name:        strings.TrimSpace(value("song")),
+		Group:       strings.TrimSpace(value("group")),
+		ReleaseDate: strings.TrimSpace(value("releaseDate")),
+		Text:        value("text"),
+		Link:        strings.TrimSpace(value("link")),
+	}
+
+	return line, item, validateImported(item)
+}
+
+func (ir *ImportReader) nextJSONL() (int, *Song, error) {
+
+	for {
+		data, err := ir.lines.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, nil, err
+		}
+		if len(data) == 0 && errors.Is(err, io.EOF) {
+			return 0, nil, io.EOF
+		}
+		ir.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		item := &Song{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		jsonErr := decoder.Decode(item)
+		if jsonErr != nil {
+			return ir.line, nil, NewValidationError("", "bad json: %s", jsonErr.Error())
+		}
+		item.ID = 0
+
+		return ir.line, item, validateImported(item)
+	}
+}
+
+func validateImported(item *Song) error {
+
+	if item.Name == "" || item.Group == "" {
+		return NewValidationError("", "song and group values must be not empty")
+	}
+
+	if item.ReleaseDate != "" {
+		_, err := ParseReleaseDate(item.ReleaseDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
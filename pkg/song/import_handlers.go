@@ -0,0 +1,174 @@
+package song
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+)
+
+const defaultImportBatchSize = 500
+
+// importContentTypes maps Content-Type of import body to format when format param is empty
+var importContentTypes = map[string]string{
+	"text/csv":                ImportCSV,
+	"application/x-ndjson":    ImportJSONL,
+	"application/jsonl":       ImportJSONL,
+	"application/x-jsonlines": ImportJSONL,
+}
+
+// @Summary Import songs
+// @Description Import songs from CSV with header or from JSON Lines, columns and fields are song, group, releaseDate, text, link.
+// @Description Body is read as a stream and saved by batches, each batch is one transaction. Release date, text and link
+// @Description missing in file are filled later by scheduled re-enrichment. Answer has result of every row.
+// @Tags songs
+// @ID import-songs
+// @Param format query string false "csv or jsonl, taken from Content-Type if empty"
+// @Param onDuplicate query string false "what to do with existing song of group: fail, skip or update" Default(fail)
+// @Param X-Actor header string false "who imports songs, recorded in revisions" Default(anonymous)
+// @Param body body string true "songs" SchemaExample(song,group,releaseDate,link\nUprising,Muse,07.09.2009,https://www.youtube.com/watch?v=w8KQmps-Sog)
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Response{created=int,updated=int,skipped=int,failed=int,rows=[]song.ImportResult}}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with marshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/import [post]
+func (sh *SongHandler) Import(w http.ResponseWriter, r *http.Request) {
+
+	storage, err := sh.actorStorage(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	importer, ok := storage.(Importer)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	//query is used instead of FormValue, so form body is not parsed
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importContentTypes[mediaType]
+	}
+	if !IsImportFormat(format) {
+		WriteError(w, r, NewValidationError("format", "must be csv or jsonl"))
+		return
+	}
+
+	onDuplicate := r.URL.Query().Get("onDuplicate")
+	if onDuplicate == "" {
+		onDuplicate = DuplicateFail
+	}
+	if !IsDuplicateMode(onDuplicate) {
+		WriteError(w, r, NewValidationError("onDuplicate", "must be fail, skip or update"))
+		return
+	}
+
+	reader, err := NewImportReader(r.Body, format)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	batchSize := sh.ImportBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	results := make([]*ImportResult, 0)
+	batch := make([]*Song, 0, batchSize)
+	lines := make([]int, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		batchResults, err := importer.ImportSongs(batch, onDuplicate)
+		if err != nil {
+			log.Printf("import batch error: [%s], lines: [%d-%d], path: [%s], method: [%s]\n", err.Error(), lines[0], lines[len(lines)-1], r.URL.Path, r.Method)
+			batchResults = make([]*ImportResult, 0, len(batch))
+			for _, item := range batch {
+				batchResults = append(batchResults, &ImportResult{
+					Status: ImportFailed,
+					Song:   item.Name,
+					Group:  item.Group,
+					Error:  "batch is not saved: " + err.Error(),
+				})
+			}
+		}
+
+		for i, result := range batchResults {
+			result.Line = lines[i]
+			results = append(results, result)
+		}
+		batch = batch[:0]
+		lines = lines[:0]
+	}
+
+	for {
+		line, item, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		//invalid row does not break batch, results are put in order of lines after the last batch
+		if errors.Is(err, ErrValidation) {
+			result := &ImportResult{
+				Line:   line,
+				Status: ImportFailed,
+				Error:  err.Error(),
+			}
+			if item != nil {
+				result.Song = item.Name
+				result.Group = item.Group
+			}
+			results = append(results, result)
+			continue
+		}
+
+		//rest of body is lost, saved rows are reported anyway
+		if err != nil {
+			log.Printf("import body read error: [%s], user agent: [%s], path: [%s], method: [%s]\n", err.Error(), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+			results = append(results, &ImportResult{
+				Line:   line,
+				Status: ImportFailed,
+				Error:  "request body read error, import is stopped",
+			})
+			break
+		}
+
+		batch = append(batch, item)
+		lines = append(lines, line)
+		if len(batch) == batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": Response{
+			ImportCreated: counts[ImportCreated],
+			ImportUpdated: counts[ImportUpdated],
+			ImportSkipped: counts[ImportSkipped],
+			ImportFailed:  counts[ImportFailed],
+			"rows":        results,
+		},
+	})
+}
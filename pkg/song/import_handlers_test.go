@@ -0,0 +1,320 @@
+package song_test
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type importAnswer struct {
+	Response struct {
+		Created int                  `json:"created"`
+		Updated int                  `json:"updated"`
+		Skipped int                  `json:"skipped"`
+		Failed  int                  `json:"failed"`
+		Rows    []*song.ImportResult `json:"rows"`
+	} `json:"response"`
+}
+
+// batchRecorder passes batches to memory storage and reports names of songs of every batch,
+// it has no As, so handler uses it without actor
+type batchRecorder struct {
+	song.Storage
+	importer song.Importer
+	batches  chan []string
+	err      error
+}
+
+func (br *batchRecorder) ImportSongs(songs []*song.Song, onDuplicate string) ([]*song.ImportResult, error) {
+
+	names := make([]string, 0, len(songs))
+	for _, item := range songs {
+		names = append(names, item.Name)
+	}
+	br.batches <- names
+
+	if br.err != nil {
+		return nil, br.err
+	}
+	return br.importer.ImportSongs(songs, onDuplicate)
+}
+
+func newBatchRecorder(err error) *batchRecorder {
+
+	memory := storage.NewMemoryStorage()
+	return &batchRecorder{
+		Storage:  memory,
+		importer: memory,
+		batches:  make(chan []string, 100),
+		err:      err,
+	}
+}
+
+func (br *batchRecorder) recorded() [][]string {
+
+	close(br.batches)
+	res := make([][]string, 0)
+	for names := range br.batches {
+		res = append(res, names)
+	}
+	return res
+}
+
+func runImport(t *testing.T, sh *song.SongHandler, query, contentType string, body io.Reader) (int, *importAnswer) {
+
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/songs/import"+query, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	sh.Import(rec, req)
+
+	answer := &importAnswer{}
+	if rec.Code == http.StatusOK {
+		err := json.Unmarshal(rec.Body.Bytes(), answer)
+		if err != nil {
+			t.Fatalf("unmarshal answer error: %v, body: %s", err, rec.Body.String())
+		}
+	}
+	return rec.Code, answer
+}
+
+type wantRow struct {
+	line   int
+	status string
+	song   string
+}
+
+func checkRows(t *testing.T, rows []*song.ImportResult, want []wantRow) {
+
+	t.Helper()
+
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if row.Line != want[i].line || row.Status != want[i].status || row.Song != want[i].song {
+			t.Errorf("row %d is {line: %d, status: %s, song: %s, error: %s}, want {line: %d, status: %s, song: %s}",
+				i, row.Line, row.Status, row.Song, row.Error, want[i].line, want[i].status, want[i].song)
+		}
+	}
+}
+
+// TestImportStreaming checks that full batch is saved before the rest of body is sent
+func TestImportStreaming(t *testing.T) {
+
+	recorder := newBatchRecorder(nil)
+	sh := &song.SongHandler{Storage: recorder, ImportBatchSize: 2}
+
+	body, writer := io.Pipe()
+	done := make(chan *importAnswer)
+	go func() {
+		_, answer := runImport(t, sh, "?format=csv", "", body)
+		done <- answer
+	}()
+
+	fmt.Fprint(writer, "song,group\nS1,G\nS2,G\n")
+	select {
+	case names := <-recorder.batches:
+		if strings.Join(names, ",") != "S1,S2" {
+			t.Errorf("first batch is %v, want [S1 S2]", names)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("first batch is not saved before end of body")
+	}
+
+	fmt.Fprint(writer, "S3,G\nS4,G\nS5,G\n")
+	writer.Close()
+	answer := <-done
+
+	batches := recorder.recorded()
+	if fmt.Sprint(batches) != "[[S3 S4] [S5]]" {
+		t.Errorf("next batches are %v, want [[S3 S4] [S5]]", batches)
+	}
+	if answer.Response.Created != 5 {
+		t.Errorf("created %d songs, want 5", answer.Response.Created)
+	}
+	checkRows(t, answer.Response.Rows, []wantRow{
+		{2, song.ImportCreated, "S1"},
+		{3, song.ImportCreated, "S2"},
+		{4, song.ImportCreated, "S3"},
+		{5, song.ImportCreated, "S4"},
+		{6, song.ImportCreated, "S5"},
+	})
+}
+
+// TestImportFailedRows checks that failed rows do not break batches and are reported in order of lines
+func TestImportFailedRows(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		batchErr    error
+		wantBatches string
+		wantRows    []wantRow
+	}{
+		{
+			name:        "jsonl",
+			contentType: "application/x-ndjson",
+			body: `{"song":"S1","group":"G"}
+{"song":"S2","group":"G","releaseDate":"32.13.2000"}
+
+{"song":"S3","group":"G"}
+{"song":"S4"}
+not json
+{"song":"S5","group":"G"}
+`,
+			wantBatches: "[[S1 S3 S5]]",
+			wantRows: []wantRow{
+				{1, song.ImportCreated, "S1"},
+				{2, song.ImportFailed, "S2"},
+				{4, song.ImportCreated, "S3"},
+				{5, song.ImportFailed, "S4"},
+				{6, song.ImportFailed, ""},
+				{7, song.ImportCreated, "S5"},
+			},
+		},
+		{
+			name:        "csv",
+			contentType: "text/csv",
+			body:        "song,group\nS1,G\n,G\nS2,G\n\"S3,G\nS4,G\n",
+			wantBatches: "[[S1 S2]]",
+			wantRows: []wantRow{
+				{2, song.ImportCreated, "S1"},
+				{3, song.ImportFailed, ""},
+				{4, song.ImportCreated, "S2"},
+				{5, song.ImportFailed, ""},
+			},
+		},
+		{
+			name:        "batch is not saved",
+			contentType: "text/csv",
+			body:        "song,group\nS1,G\nS2\nS3,G\n",
+			batchErr:    errors.New("db is down"),
+			wantBatches: "[[S1 S3]]",
+			wantRows: []wantRow{
+				{2, song.ImportFailed, "S1"},
+				{3, song.ImportFailed, "S2"},
+				{4, song.ImportFailed, "S3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			recorder := newBatchRecorder(tt.batchErr)
+			sh := &song.SongHandler{Storage: recorder, ImportBatchSize: 10}
+
+			code, answer := runImport(t, sh, "", tt.contentType, strings.NewReader(tt.body))
+			if code != http.StatusOK {
+				t.Fatalf("status is %d, want 200", code)
+			}
+
+			batches := recorder.recorded()
+			if fmt.Sprint(batches) != tt.wantBatches {
+				t.Errorf("batches are %v, want %s", batches, tt.wantBatches)
+			}
+			checkRows(t, answer.Response.Rows, tt.wantRows)
+			for _, row := range answer.Response.Rows {
+				if row.Status == song.ImportFailed && row.Error == "" {
+					t.Errorf("failed row at line %d has no error", row.Line)
+				}
+			}
+		})
+	}
+}
+
+func TestImportOnDuplicate(t *testing.T) {
+
+	const body = "song,group,link\nUprising,Muse,https://example.com/new\nStarlight,Muse,\nHysteria,Muse,https://example.com/hysteria\n"
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantRows []wantRow
+		wantLink string
+	}{
+		{
+			name:     "fail by default",
+			wantCode: http.StatusOK,
+			wantRows: []wantRow{
+				{2, song.ImportFailed, "Uprising"},
+				{3, song.ImportFailed, "Starlight"},
+				{4, song.ImportCreated, "Hysteria"},
+			},
+			wantLink: "https://example.com/old",
+		},
+		{
+			name:     "skip",
+			query:    "&onDuplicate=skip",
+			wantCode: http.StatusOK,
+			wantRows: []wantRow{
+				{2, song.ImportSkipped, "Uprising"},
+				{3, song.ImportSkipped, "Starlight"},
+				{4, song.ImportCreated, "Hysteria"},
+			},
+			wantLink: "https://example.com/old",
+		},
+		{
+			//row without values to change is skipped
+			name:     "update",
+			query:    "&onDuplicate=update",
+			wantCode: http.StatusOK,
+			wantRows: []wantRow{
+				{2, song.ImportUpdated, "Uprising"},
+				{3, song.ImportSkipped, "Starlight"},
+				{4, song.ImportCreated, "Hysteria"},
+			},
+			wantLink: "https://example.com/new",
+		},
+		{
+			name:     "unknown mode",
+			query:    "&onDuplicate=replace",
+			wantCode: http.StatusBadRequest,
+			wantLink: "https://example.com/old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			memory := storage.NewMemoryStorage()
+			id, err := memory.Add("Uprising", "Muse", "07.09.2009", "", "https://example.com/old")
+			if err != nil {
+				t.Fatalf("add error: %v", err)
+			}
+			_, err = memory.Add("Starlight", "Muse", "", "", "")
+			if err != nil {
+				t.Fatalf("add error: %v", err)
+			}
+			sh := &song.SongHandler{Storage: memory}
+
+			code, answer := runImport(t, sh, "?format=csv"+tt.query, "", strings.NewReader(body))
+			if code != tt.wantCode {
+				t.Fatalf("status is %d, want %d", code, tt.wantCode)
+			}
+			checkRows(t, answer.Response.Rows, tt.wantRows)
+
+			item, err := memory.GetSong(id)
+			if err != nil {
+				t.Fatalf("get song error: %v", err)
+			}
+			if item.Link != tt.wantLink {
+				t.Errorf("link is %s, want %s", item.Link, tt.wantLink)
+			}
+		})
+	}
+}
@@ -2,36 +2,132 @@ package song
 
 import (
 	"encoding/json"
+	"log"
+	"net/http"
 	"time"
 )
 
 // Song model info
 // @Description song information
 type Song struct {
-	ID          int    `json:"id"`
-	Name        string `json:"song"`
-	Group       string `json:"group"`
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+	ID          int       `json:"id"`
+	Name        string    `json:"song"`
+	Group       string    `json:"group"`
+	ReleaseDate string    `json:"releaseDate"`
+	Text        string    `json:"text"`
+	Link        string    `json:"link"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	//Version grows with every change of song, it is sent as ETag
+	Version int    `json:"-"`
+	ETag    string `json:"etag"`
 }
 
 type SongHandler struct {
 	Storage
-	ExternalAPI string
+	FuzzyThreshold  float64
+	ImportBatchSize int
+	//RequireIfMatch makes If-Match header required for change and delete of song
+	RequireIfMatch bool
 }
 
 type Storage interface {
 	Add(name, group, releaseDate, text, link string) (int, error)
 	Delete(id int) error
 	Update(id int, releaseDate, text, link string) error
-	Get(id int) (string, error)
-	GetAll(limit, offset int, songNameFragment, groupNameFragment, year, textFragment, linkExist string) ([]*Song, error)
-	GetErrorAlreadyExist() error
-	GetErrorNoUpdate() error
-	GetErrorBadID() error
-	GetErrorBadDate() error
-	GetErrorNoRows() error
+	GetAll(filter Filter) ([]*Song, error)
+	GetSong(id int) (*Song, error)
+	//GetVerses returns verses of song ordered by position and number of all its verses
+	GetVerses(id, limit, offset int) ([]*Verse, int, error)
+	GetVerse(id, position int) (*Verse, error)
+	//UpdateVerse replaces text of verse at verse.Position, empty kind keeps the stored one, song text is rebuilt from verses
+	UpdateVerse(id int, verse *Verse) error
+}
+
+// Filter holds parameters of songs list, empty fields are not used for filtering
+type Filter struct {
+	Limit    int
+	Offset   int
+	SongName string
+	Group    string
+	Year     string
+	Text     string
+	Link     string
+	AlbumID  int
+	Tags     []string
+	AnyTag   bool    //songs with any of Tags instead of songs with all of them
+	Cursor   *Cursor //songs after cursor, Offset is not used with it
+	Sort     []SortKey
+	//songs with release date period within [ReleasedFrom, ReleasedBefore), zero time is not used
+	ReleasedFrom   time.Time
+	ReleasedBefore time.Time
+	Query          *Query //parsed q parameter
+}
+
+// SearchResult model info
+// @Description song found by full-text search, headline is the best matching verse with highlighted words
+type SearchResult struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"song"`
+	Group       string  `json:"group"`
+	ReleaseDate string  `json:"releaseDate"`
+	Link        string  `json:"link"`
+	Rank        float64 `json:"rank"`
+	Headline    string  `json:"headline"`
+}
+
+// Searcher is implemented by storages with full-text search over lyrics
+type Searcher interface {
+	Search(query, language string, limit, offset int) ([]*SearchResult, error)
+}
+
+// SimilarSong model info
+// @Description song found by fuzzy search with its similarity score
+type SimilarSong struct {
+	Song
+	Similarity float64 `json:"similarity"`
+}
+
+// Suggestion model info
+// @Description the closest existing song and group names for search that found nothing
+type Suggestion struct {
+	Song  string `json:"song,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+// FuzzyFinder is implemented by storages with typo-tolerant search by song and group names,
+// threshold is minimal similarity from 0 to 1
+type FuzzyFinder interface {
+	FindSimilar(filter Filter, threshold float64) ([]*SimilarSong, error)
+	Suggest(songName, group string, threshold float64) (*Suggestion, error)
+}
+
+const (
+	EnrichmentPending = "pending"
+	EnrichmentRunning = "running"
+	EnrichmentDone    = "done"
+	EnrichmentFailed  = "failed"
+)
+
+// Enrichment model info
+// @Description state of background job filling release date, text and link of song from info API
+type Enrichment struct {
+	SongID    int       `json:"songId"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	NextRunAt time.Time `json:"nextRunAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// EnrichmentQueue is implemented by storages keeping enrichment jobs, jobs are processed by enrich.Worker
+type EnrichmentQueue interface {
+	//EnqueueEnrichment creates pending job for song or resets existing one to pending
+	EnqueueEnrichment(songID int) (*Enrichment, error)
+	//AddWithEnrichment does the same as Storage.Add and creates pending job for new song in one transaction,
+	//so song is never left without job
+	AddWithEnrichment(name, group, releaseDate, text, link string) (int, *Enrichment, error)
+	GetEnrichment(songID int) (*Enrichment, error)
 }
 
 // @Description response format
@@ -53,3 +149,22 @@ func GetAnswerWithError(message, path string) ([]byte, error) {
 
 	return answerData, nil
 }
+
+// WriteResponse marshals response and sends it with status
+func WriteResponse(w http.ResponseWriter, r *http.Request, status int, response Response) {
+
+	dataResponse, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("marshal response error: [%s], path: [%s], method: [%s]\n", err.Error(), r.URL.Path, r.Method)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	_, err = w.Write(dataResponse)
+	if err != nil {
+		log.Printf("sending response error: [%s], user agent: [%s], path: [%s], method: [%s]\n", err.Error(), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	} else {
+		log.Printf("response sended: [%s], user agent: [%s], path: [%s], method: [%s]\n", string(dataResponse), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	}
+}
@@ -0,0 +1,61 @@
+package song
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// ReadPagination reads optional limit and offset query values, zero means no limit or no offset
+func ReadPagination(r *http.Request) (limit, offset int, err error) {
+
+	if r.FormValue("limit") != "" {
+		limit, err = strconv.Atoi(r.FormValue("limit"))
+		if err != nil {
+			return 0, 0, NewValidationError("limit", "must be number")
+		}
+	}
+
+	if r.FormValue("offset") != "" {
+		offset, err = strconv.Atoi(r.FormValue("offset"))
+		if err != nil {
+			return 0, 0, NewValidationError("offset", "must be number")
+		}
+	}
+
+	if offset < 0 || limit < 0 {
+		return 0, 0, NewValidationError("", "offset or limit cannot be negative")
+	}
+
+	return limit, offset, nil
+}
+
+// ReadJSON unmarshals request body to v, bad body is reported as validation error
+func ReadJSON(r *http.Request, v interface{}) error {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("request body read error: [%s], user agent: [%s], path: [%s], method: [%s]\n", err.Error(), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+		return NewValidationError("body", "request body read error")
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return NewValidationError("body", "bad json: %s", err.Error())
+	}
+
+	return nil
+}
+
+// ReadPathID reads integer id from path value
+func ReadPathID(r *http.Request, name string) (int, error) {
+
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		return 0, NewValidationError(name, "must be number")
+	}
+
+	return id, nil
+}
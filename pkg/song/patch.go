@@ -0,0 +1,83 @@
+package song
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SongPatch is JSON merge patch of song (RFC 7396), nil field keeps stored value,
+// empty value clears release date, text or link
+type SongPatch struct {
+	Name        *string
+	Group       *string
+	ReleaseDate *string
+	Text        *string
+	Link        *string
+}
+
+// Patcher is implemented by storages which apply merge patch to song, song and group are checked for uniqueness
+// like in Add, change is recorded as update revision
+type Patcher interface {
+	Patch(id int, patch *SongPatch) error
+}
+
+// readOnlyFields are fields of Song which are set by storage
+var readOnlyFields = map[string]bool{
+	"id":        true,
+	"createdAt": true,
+	"updatedAt": true,
+	"etag":      true,
+}
+
+// ParseMergePatch reads merge patch of song, null value clears field, song and group cannot be cleared,
+// unknown and read-only fields are rejected
+func ParseMergePatch(data []byte) (*SongPatch, error) {
+
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil || fields == nil {
+		return nil, NewValidationError("body", "merge patch must be JSON object")
+	}
+
+	patch := &SongPatch{}
+	targets := map[string]**string{
+		"song":        &patch.Name,
+		"group":       &patch.Group,
+		"releaseDate": &patch.ReleaseDate,
+		"text":        &patch.Text,
+		"link":        &patch.Link,
+	}
+
+	for name, raw := range fields {
+		target, ok := targets[name]
+		if !ok {
+			if readOnlyFields[name] {
+				return nil, NewValidationError(name, "is read-only")
+			}
+			return nil, NewValidationError(name, "unknown field")
+		}
+
+		value := ""
+		if !bytes.Equal(raw, []byte("null")) {
+			err = json.Unmarshal(raw, &value)
+			if err != nil {
+				return nil, NewValidationError(name, "must be string or null")
+			}
+		}
+		*target = &value
+	}
+
+	if patch.Name != nil && *patch.Name == "" {
+		return nil, NewValidationError("song", "cannot be cleared")
+	}
+	if patch.Group != nil && *patch.Group == "" {
+		return nil, NewValidationError("group", "cannot be cleared")
+	}
+
+	return patch, nil
+}
+
+// Empty tells that patch changes nothing
+func (p *SongPatch) Empty() bool {
+	return p.Name == nil && p.Group == nil && p.ReleaseDate == nil && p.Text == nil && p.Link == nil
+}
@@ -0,0 +1,359 @@
+package song
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// operations of Query
+const (
+	QueryAnd   = "and"
+	QueryOr    = "or"
+	QueryNot   = "not"
+	QueryMatch = "match"
+)
+
+// fields of Query predicates, QueryAny is a term without field which is searched in song and group names
+const (
+	QuerySong     = "song"
+	QueryGroup    = "group"
+	QueryText     = "text"
+	QueryLink     = "link"
+	QueryReleased = "released"
+	QueryAny      = "any"
+)
+
+// Query is parsed q parameter of songs list. And, Or and Not join Args, Match is predicate on Field:
+// Value is part of song, group name or text, "true" or "false" for link, release period is [From, Before)
+// like in Filter, one of its bounds may be zero
+type Query struct {
+	Op     string
+	Args   []*Query
+	Field  string
+	Value  string
+	From   time.Time
+	Before time.Time
+}
+
+// ParseQuery reads query like `group:muse year:2006..2010 text:"black hole" -link:false`.
+// Terms are joined by AND, OR joins groups of terms, "-" or NOT negates term, parentheses group terms.
+// Fields are song, group, text (parts of them), link (true or false), year (year, decade like 1990s
+// or range of them like 2006..2010, 2006.. or ..2010) and released (the same with dates and months).
+// Term without field is searched in song and group names
+func ParseQuery(value string) (*Query, error) {
+
+	p := &queryParser{input: []rune(value)}
+	query, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf(p.pos, "unexpected %q", p.input[p.pos])
+	}
+
+	return query, nil
+}
+
+type queryParser struct {
+	input []rune
+	pos   int
+}
+
+// errorf returns validation error of q with 1-based position of the wrong part
+func (p *queryParser) errorf(pos int, format string, args ...interface{}) error {
+	return NewValidationError("q", "position %d: %s", pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// isStop tells whether rune ends word
+func isStop(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+}
+
+// keyword consumes OR, AND or NOT written in upper case as separate word
+func (p *queryParser) keyword(word string) bool {
+
+	end := p.pos + len(word)
+	if end > len(p.input) || string(p.input[p.pos:end]) != word {
+		return false
+	}
+	if end < len(p.input) && !isStop(p.input[end]) {
+		return false
+	}
+
+	p.pos = end
+	return true
+}
+
+// termFollows tells whether the rest of input starts with search term, not with its end, ")" or OR, AND
+func (p *queryParser) termFollows() bool {
+
+	p.skipSpaces()
+	start := p.pos
+	defer func() { p.pos = start }()
+
+	return p.pos < len(p.input) && p.input[p.pos] != ')' && !p.keyword("OR") && !p.keyword("AND")
+}
+
+func (p *queryParser) parseOr() (*Query, error) {
+
+	args := make([]*Query, 0, 1)
+	for {
+		arg, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		p.skipSpaces()
+		start := p.pos
+		if !p.keyword("OR") {
+			break
+		}
+		if !p.termFollows() {
+			return nil, p.errorf(start, "expected search term after OR")
+		}
+	}
+
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return &Query{Op: QueryOr, Args: args}, nil
+}
+
+func (p *queryParser) parseAnd() (*Query, error) {
+
+	args := make([]*Query, 0, 1)
+	for {
+		p.skipSpaces()
+		start := p.pos
+		if p.pos == len(p.input) || p.input[p.pos] == ')' || p.keyword("OR") {
+			p.pos = start
+			break
+		}
+
+		//AND is the same as space between terms
+		if p.keyword("AND") {
+			if len(args) == 0 {
+				return nil, p.errorf(start, "AND must follow search term")
+			}
+			if !p.termFollows() {
+				return nil, p.errorf(start, "expected search term after AND")
+			}
+		}
+
+		arg, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+
+	if len(args) == 0 {
+		start := p.pos
+		switch {
+		case p.pos == len(p.input):
+			return nil, p.errorf(p.pos, "expected search term at the end of query")
+		case p.keyword("OR"):
+			return nil, p.errorf(start, "OR must be between search terms")
+		}
+		return nil, p.errorf(p.pos, "expected search term before %q", p.input[p.pos])
+	}
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return &Query{Op: QueryAnd, Args: args}, nil
+}
+
+func (p *queryParser) parseUnary() (*Query, error) {
+
+	p.skipSpaces()
+	start := p.pos
+	negated := p.keyword("NOT")
+	if !negated && p.pos < len(p.input) && p.input[p.pos] == '-' {
+		p.pos++
+		if p.pos == len(p.input) || unicode.IsSpace(p.input[p.pos]) {
+			return nil, p.errorf(start, "- must be followed by search term without space")
+		}
+		negated = true
+	}
+
+	if !negated {
+		return p.parsePrimary()
+	}
+
+	arg, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &Query{Op: QueryNot, Args: []*Query{arg}}, nil
+}
+
+func (p *queryParser) parsePrimary() (*Query, error) {
+
+	p.skipSpaces()
+	if p.pos == len(p.input) {
+		return nil, p.errorf(p.pos, "expected search term at the end of query")
+	}
+
+	start := p.pos
+	if p.keyword("OR") || p.keyword("AND") {
+		return nil, p.errorf(start, "%s must be between search terms", string(p.input[start:p.pos]))
+	}
+
+	switch p.input[p.pos] {
+	case '(':
+		p.pos++
+		query, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.pos == len(p.input) || p.input[p.pos] != ')' {
+			return nil, p.errorf(start, "parenthesis is not closed")
+		}
+		p.pos++
+		return query, nil
+
+	case ')':
+		return nil, p.errorf(start, "unexpected %q", ')')
+
+	case '"':
+		phrase, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		return &Query{Op: QueryMatch, Field: QueryAny, Value: phrase}, nil
+	}
+
+	for p.pos < len(p.input) && !isStop(p.input[p.pos]) && p.input[p.pos] != ':' {
+		p.pos++
+	}
+	word := string(p.input[start:p.pos])
+
+	if p.pos == len(p.input) || p.input[p.pos] != ':' {
+		return &Query{Op: QueryMatch, Field: QueryAny, Value: word}, nil
+	}
+	if word == "" {
+		return nil, p.errorf(start, "expected field name before %q", ':')
+	}
+
+	p.pos++
+	return p.parseField(start, word)
+}
+
+// parseQuoted reads phrase in double quotes, \" and \\ are quote and backslash inside phrase
+func (p *queryParser) parseQuoted() (string, error) {
+
+	start := p.pos
+	p.pos++
+
+	var phrase strings.Builder
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		p.pos++
+		switch {
+		case r == '"':
+			return phrase.String(), nil
+		case r == '\\' && p.pos < len(p.input) && (p.input[p.pos] == '"' || p.input[p.pos] == '\\'):
+			phrase.WriteRune(p.input[p.pos])
+			p.pos++
+		default:
+			phrase.WriteRune(r)
+		}
+	}
+
+	return "", p.errorf(start, "quoted phrase is not closed")
+}
+
+// parseField reads value of field which starts at fieldPos, value is quoted phrase or word up to space or ")"
+func (p *queryParser) parseField(fieldPos int, field string) (*Query, error) {
+
+	valuePos := p.pos
+	var value string
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		var err error
+		value, err = p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) && p.input[p.pos] != ')' {
+			p.pos++
+		}
+		value = string(p.input[valuePos:p.pos])
+	}
+
+	if value == "" {
+		return nil, p.errorf(valuePos, "expected value of field %s", field)
+	}
+
+	query := &Query{Op: QueryMatch, Field: field, Value: value}
+	switch field {
+	case QuerySong, QueryGroup, QueryText:
+		return query, nil
+
+	case QueryLink:
+		if value != "true" && value != "false" {
+			return nil, p.errorf(valuePos, "link must be true or false")
+		}
+		return query, nil
+
+	case "year", QueryReleased:
+		query.Field = QueryReleased
+		from, to, found := strings.Cut(value, "..")
+		if !found {
+			to = from
+		}
+		if from == "" && to == "" {
+			return nil, p.errorf(valuePos, "range must have at least one bound")
+		}
+
+		var err error
+		if from != "" {
+			query.From, _, err = p.parsePeriod(field, from, valuePos)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if to != "" {
+			_, query.Before, err = p.parsePeriod(field, to, valuePos+len([]rune(value))-len([]rune(to)))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !query.From.IsZero() && !query.Before.IsZero() && !query.From.Before(query.Before) {
+			return nil, p.errorf(valuePos, "range end is before its start")
+		}
+		return query, nil
+	}
+
+	return nil, p.errorf(fieldPos, "unknown field %q, use: song, group, text, link, year, released", field)
+}
+
+// parsePeriod reads bound of year or released range at pos
+func (p *queryParser) parsePeriod(field, value string, pos int) (time.Time, time.Time, error) {
+
+	if field == "year" {
+		digits := strings.TrimSuffix(value, "s")
+		if len(digits) != 4 || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+			return time.Time{}, time.Time{}, p.errorf(pos, "year must be year or decade like 1990s, got %q", value)
+		}
+	}
+
+	start, end, err := ParseReleasePeriod("q", value)
+	if err != nil {
+		return time.Time{}, time.Time{}, p.errorf(pos, "%s must be date, month, year or decade like 1990s, got %q", field, value)
+	}
+
+	return start, end, nil
+}
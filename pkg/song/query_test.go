@@ -0,0 +1,113 @@
+package song
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatQuery writes query tree like and(group:muse,not(link:false)), periods are written as [from,before)
+func formatQuery(q *Query) string {
+
+	if q.Op != QueryMatch {
+		args := make([]string, 0, len(q.Args))
+		for _, arg := range q.Args {
+			args = append(args, formatQuery(arg))
+		}
+		return q.Op + "(" + strings.Join(args, ",") + ")"
+	}
+
+	if q.Field != QueryReleased {
+		return q.Field + ":" + q.Value
+	}
+
+	bound := func(value string) string {
+		if strings.HasPrefix(value, "0001") {
+			return ""
+		}
+		return value
+	}
+	return fmt.Sprintf("released:[%s,%s)", bound(q.From.Format("2006-01-02")), bound(q.Before.Format("2006-01-02")))
+}
+
+func TestParseQuery(t *testing.T) {
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"muse", "any:muse"},
+		{"group:muse year:2006..2010", "and(group:muse,released:[2006-01-01,2011-01-01))"},
+		{"group:muse AND -link:false", "and(group:muse,not(link:false))"},
+		{`text:"black hole" "new \"age\""`, `and(text:black hole,any:new "age")`},
+		{"song:a OR song:b group:c", "or(song:a,and(song:b,group:c))"},
+		{"(song:a OR song:b) group:c", "and(or(song:a,song:b),group:c)"},
+		{"NOT (a OR b)", "not(or(any:a,any:b))"},
+		{"--a", "not(not(any:a))"},
+		{"year:1990s..", "released:[1990-01-01,)"},
+		{"released:..07.2009", "released:[,2009-08-01)"},
+		{"released:16.07.2006", "released:[2006-07-16,2006-07-17)"},
+		{"ANDROID ORBIT", "and(any:ANDROID,any:ORBIT)"},
+		{"and or", "and(any:and,any:or)"},
+		{"  group:muse  ", "group:muse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+
+			query, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if got := formatQuery(query); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "position 1: expected search term at the end of query"},
+		{"group:muse AND", "position 12: expected search term after AND"},
+		{"group:muse AND  ", "position 12: expected search term after AND"},
+		{"(a AND) b", "position 4: expected search term after AND"},
+		{"a AND OR b", "position 3: expected search term after AND"},
+		{"a AND AND b", "position 3: expected search term after AND"},
+		{"AND a", "position 1: AND must follow search term"},
+		{"a OR", "position 3: expected search term after OR"},
+		{"a OR AND b", "position 3: expected search term after OR"},
+		{"OR a", "position 1: OR must be between search terms"},
+		{"a NOT", "position 6: expected search term at the end of query"},
+		{"a - b", "position 3: - must be followed by search term without space"},
+		{"(a b", "position 1: parenthesis is not closed"},
+		{"a) b", "position 2: unexpected ')'"},
+		{`text:"black`, "position 6: quoted phrase is not closed"},
+		{":muse", "position 1: expected field name before ':'"},
+		{"group:", "position 7: expected value of field group"},
+		{"album:x", `position 1: unknown field "album", use: song, group, text, link, year, released`},
+		{"link:yes", "position 6: link must be true or false"},
+		{"year:2006..20", `position 12: year must be year or decade like 1990s, got "20"`},
+		{"year:2010..2006", "position 6: range end is before its start"},
+		{"released:..", "position 10: range must have at least one bound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+
+			_, err := ParseQuery(tt.query)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != "q" {
+				t.Fatalf("got %v, want validation error of q", err)
+			}
+			if got := strings.TrimPrefix(err.Error(), "q: "); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
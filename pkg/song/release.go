@@ -0,0 +1,95 @@
+package song
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// precisions of release date, date known to month or year is stored as the first day of that period
+const (
+	PrecisionDay   = "day"
+	PrecisionMonth = "month"
+	PrecisionYear  = "year"
+)
+
+var releaseLayouts = []struct {
+	layout    string
+	precision string
+}{
+	{"02.01.2006", PrecisionDay},
+	{"01.2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// ReleaseDate is release date known to day, month or year, Start is the first day of that period
+type ReleaseDate struct {
+	Start     time.Time
+	Precision string
+}
+
+// ParseReleaseDate reads date in format day.month.year, month.year or year
+func ParseReleaseDate(value string) (ReleaseDate, error) {
+
+	for _, item := range releaseLayouts {
+		start, err := time.Parse(item.layout, value)
+		if err == nil {
+			return ReleaseDate{Start: start, Precision: item.precision}, nil
+		}
+	}
+
+	return ReleaseDate{}, NewValidationError("releaseDate", "date must have format day.month.year, month.year or year")
+}
+
+// NewReleaseDate returns release date stored as start and precision, unknown precision means day
+func NewReleaseDate(start time.Time, precision string) ReleaseDate {
+
+	if precision != PrecisionMonth && precision != PrecisionYear {
+		precision = PrecisionDay
+	}
+	return ReleaseDate{Start: start, Precision: precision}
+}
+
+// String formats date with its precision, so it is read back by ParseReleaseDate
+func (d ReleaseDate) String() string {
+
+	for _, item := range releaseLayouts {
+		if item.precision == d.Precision {
+			return d.Start.Format(item.layout)
+		}
+	}
+	return d.Start.Format("02.01.2006")
+}
+
+// End returns the day after the last day of period of release date
+func (d ReleaseDate) End() time.Time {
+
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Start.AddDate(1, 0, 0)
+	case PrecisionMonth:
+		return d.Start.AddDate(0, 1, 0)
+	}
+	return d.Start.AddDate(0, 0, 1)
+}
+
+// ParseReleasePeriod reads bound of release date filter as date, month, year or decade like 1990s
+// and returns the first day of period and the day after its last day, field is name of parameter for error
+func ParseReleasePeriod(field, value string) (time.Time, time.Time, error) {
+
+	if decade, ok := strings.CutSuffix(value, "s"); ok {
+		year, err := strconv.Atoi(decade)
+		if err != nil || len(decade) != 4 || year%10 != 0 {
+			return time.Time{}, time.Time{}, NewValidationError(field, "decade must have format like 1990s")
+		}
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(10, 0, 0), nil
+	}
+
+	date, err := ParseReleaseDate(value)
+	if err != nil {
+		return time.Time{}, time.Time{}, NewValidationError(field, "must have format day.month.year, month.year, year or decade like 1990s")
+	}
+
+	return date.Start, date.End(), nil
+}
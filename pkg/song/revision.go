@@ -0,0 +1,212 @@
+package song
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// actions of Revision
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// actors of changes made without user request, changes of request are recorded with X-Actor header
+const (
+	ActorSystem    = "system"
+	ActorAnonymous = "anonymous"
+)
+
+// maxActorLength is length limit of actor column
+const maxActorLength = 100
+
+// SongState model info
+// @Description values of song fields saved in revision
+type SongState struct {
+	Name        string `json:"song"`
+	Group       string `json:"group"`
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// RevisionChange model info
+// @Description changed field of song with its values before and after revision
+type RevisionChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// Revision model info
+// @Description immutable record of song change, state is song after revision, it is null after delete
+type Revision struct {
+	ID        int               `json:"id"`
+	SongID    int               `json:"songId"`
+	Action    string            `json:"action"`
+	Actor     string            `json:"actor"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Changes   []*RevisionChange `json:"changes"`
+	State     *SongState        `json:"state"`
+	//Previous is state before revision, nil for create
+	Previous *SongState `json:"-"`
+}
+
+// RevisionDiff model info
+// @Description difference between song states after two revisions, text lines are prefixed with "+ ", "- " or two spaces
+type RevisionDiff struct {
+	From     int               `json:"from"`
+	To       int               `json:"to"`
+	Changes  []*RevisionChange `json:"changes"`
+	TextDiff []string          `json:"textDiff"`
+}
+
+// Revisioner is implemented by storages which record every create, update and delete of song as revision.
+// Changes made through storage returned by As are recorded with actor, other changes with ActorSystem
+type Revisioner interface {
+	As(actor string) Storage
+	//GetRevisions returns revisions of song, the latest first, revisions of deleted song are kept
+	GetRevisions(songID, limit, offset int) ([]*Revision, error)
+	GetRevision(songID, revisionID int) (*Revision, error)
+	//RestoreRevision sets song, group, release date, text and link of song to its state after revision,
+	//ErrConflict is returned when other song has the same song and group, restore is recorded as new revision which is returned
+	RestoreRevision(songID, revisionID int) (*Revision, error)
+}
+
+// NewRevision returns revision with changes between previous and state, nil state has empty fields
+func NewRevision(id, songID int, action, actor string, createdAt time.Time, previous, state *SongState) *Revision {
+	return &Revision{
+		ID:        id,
+		SongID:    songID,
+		Action:    action,
+		Actor:     actor,
+		CreatedAt: createdAt,
+		Changes:   StateChanges(previous, state),
+		State:     state,
+		Previous:  previous,
+	}
+}
+
+// StateChanges returns fields which differ in old and new states, nil state has empty fields
+func StateChanges(old, new *SongState) []*RevisionChange {
+
+	if old == nil {
+		old = &SongState{}
+	}
+	if new == nil {
+		new = &SongState{}
+	}
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"song", old.Name, new.Name},
+		{"group", old.Group, new.Group},
+		{"releaseDate", old.ReleaseDate, new.ReleaseDate},
+		{"text", old.Text, new.Text},
+		{"link", old.Link, new.Link},
+	}
+
+	changes := make([]*RevisionChange, 0)
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, &RevisionChange{
+				Field:    field.name,
+				OldValue: field.old,
+				NewValue: field.new,
+			})
+		}
+	}
+
+	return changes
+}
+
+// DiffRevisions compares song states after revisions from and to
+func DiffRevisions(from, to *Revision) *RevisionDiff {
+
+	diff := &RevisionDiff{
+		From:     from.ID,
+		To:       to.ID,
+		Changes:  StateChanges(from.State, to.State),
+		TextDiff: []string{},
+	}
+
+	var oldText, newText string
+	if from.State != nil {
+		oldText = from.State.Text
+	}
+	if to.State != nil {
+		newText = to.State.Text
+	}
+	if oldText != newText {
+		diff.TextDiff = DiffLines(oldText, newText)
+	}
+
+	return diff
+}
+
+// DiffLines returns lines of both texts, removed lines are prefixed with "- ", added lines with "+ ",
+// lines of the longest common subsequence with two spaces
+func DiffLines(old, new string) []string {
+
+	a, b := splitLines(old), splitLines(new)
+
+	//common[i][j] is length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+
+	return lines
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// RequestActor reads actor of request from X-Actor header, request without it is made by ActorAnonymous
+func RequestActor(r *http.Request) (string, error) {
+
+	actor := strings.TrimSpace(r.Header.Get("X-Actor"))
+	if actor == "" {
+		return ActorAnonymous, nil
+	}
+	if len([]rune(actor)) > maxActorLength {
+		return "", NewValidationError("X-Actor", "must be at most %d characters", maxActorLength)
+	}
+
+	return actor, nil
+}
@@ -0,0 +1,216 @@
+package song
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// actorStorage returns storage which records changes with actor of request when storage keeps revisions
+func (sh *SongHandler) actorStorage(r *http.Request) (Storage, error) {
+
+	revisioner, ok := sh.Storage.(Revisioner)
+	if !ok {
+		return sh.Storage, nil
+	}
+
+	actor, err := RequestActor(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisioner.As(actor), nil
+}
+
+// @Summary Get song revisions
+// @Description Get revisions of song, the latest first. Every create, update, delete and restore of song is recorded with changed fields, time and actor from X-Actor header, revisions of deleted song are kept
+// @Tags revisions
+// @ID get-revisions
+// @Param id path int true "song id"
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Produce json
+// @Success 200 {object} song.Response{response=[]song.Revision}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/{id}/revisions [get]
+func (sh *SongHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+
+	revisioner, ok := sh.Storage.(Revisioner)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	limit, offset, err := ReadPagination(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	revisions, err := revisioner.GetRevisions(id, limit, offset)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": revisions,
+	})
+}
+
+// @Summary Get song revision
+// @Description Get single revision of song with its changes and state of song after it
+// @Tags revisions
+// @ID get-revision
+// @Param id path int true "song id"
+// @Param revision path int true "revision id"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Revision}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/{id}/revisions/{revision} [get]
+func (sh *SongHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+
+	revisioner, ok := sh.Storage.(Revisioner)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	revisionID, err := ReadPathID(r, "revision")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	revision, err := revisioner.GetRevision(id, revisionID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": revision,
+	})
+}
+
+// @Summary Diff two song revisions
+// @Description Compare states of song after two revisions, changes contain fields which differ, textDiff contains lines of text with "+ " for added and "- " for removed lines
+// @Tags revisions
+// @ID diff-revisions
+// @Param id path int true "song id"
+// @Param from query int true "revision id of old state"
+// @Param to query int true "revision id of new state"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.RevisionDiff}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/{id}/revisions/diff [get]
+func (sh *SongHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+
+	revisioner, ok := sh.Storage.(Revisioner)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	revisions := make([]*Revision, 0, 2)
+	for _, name := range []string{"from", "to"} {
+		revisionID, err := strconv.Atoi(r.FormValue(name))
+		if err != nil {
+			WriteError(w, r, NewValidationError(name, "must be revision id"))
+			return
+		}
+
+		revision, err := revisioner.GetRevision(id, revisionID)
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+		revisions = append(revisions, revision)
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": DiffRevisions(revisions[0], revisions[1]),
+	})
+}
+
+// @Summary Restore song to revision
+// @Description Set song, group, release date, text and link of song to its state after revision, restore is recorded as new revision.
+// @Description Revision of delete cannot be restored, restore of song and group taken by other song is rejected with 409
+// @Tags revisions
+// @ID restore-revision
+// @Param id path int true "song id"
+// @Param revision path int true "revision id"
+// @Param X-Actor header string false "who restores song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is restored only when it is not changed by others since read"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Revision}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/songs/{id}/revisions/{revision}/restore [post]
+func (sh *SongHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+
+	if _, ok := sh.Storage.(Revisioner); !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	revisionID, err := ReadPathID(r, "revision")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	storage, err := sh.songStorage(r, id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	revision, err := storage.(Revisioner).RestoreRevision(id, revisionID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("song restored, id: [%d], revision: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, revisionID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": revision,
+	})
+}
@@ -0,0 +1,71 @@
+package song_test
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// TestRestoreRevisionIfMatch checks that restore is a change of song with the same preconditions as update
+func TestRestoreRevisionIfMatch(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		requireIfMatch bool
+		wantCode       int
+	}{
+		{"without If-Match", "", false, http.StatusOK},
+		{"current ETag", `"2"`, false, http.StatusOK},
+		{"old ETag", `"1"`, false, http.StatusPreconditionFailed},
+		{"If-Match is required", "", true, http.StatusPreconditionRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			memory := storage.NewMemoryStorage()
+			id, err := memory.Add("Uprising", "Muse", "07.09.2009", "", "")
+			if err != nil {
+				t.Fatalf("add error: %v", err)
+			}
+			err = memory.Update(id, "", "new text", "")
+			if err != nil {
+				t.Fatalf("update error: %v", err)
+			}
+			revisions, err := memory.GetRevisions(id, 0, 0)
+			if err != nil || len(revisions) != 2 {
+				t.Fatalf("revisions: got %v, %v, want 2", revisions, err)
+			}
+			sh := &song.SongHandler{Storage: memory, RequireIfMatch: tt.requireIfMatch}
+
+			created := strconv.Itoa(revisions[1].ID)
+			req := httptest.NewRequest(http.MethodPost, "/api/songs/"+strconv.Itoa(id)+"/revisions/"+created+"/restore", nil)
+			req.SetPathValue("id", strconv.Itoa(id))
+			req.SetPathValue("revision", created)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			sh.RestoreRevision(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status is %d, want %d, body: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			item, err := memory.GetSong(id)
+			if err != nil {
+				t.Fatalf("get song error: %v", err)
+			}
+			wantText := "new text"
+			if tt.wantCode == http.StatusOK {
+				wantText = ""
+			}
+			if item.Text != wantText {
+				t.Errorf("text is %q, want %q", item.Text, wantText)
+			}
+		})
+	}
+}
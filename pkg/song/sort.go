@@ -0,0 +1,118 @@
+package song
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fields of songs list which can be used in sort parameter
+const (
+	SortName        = "song"
+	SortGroup       = "group"
+	SortReleaseDate = "releaseDate"
+	SortID          = "id"
+	SortCreatedAt   = "createdAt"
+	SortUpdatedAt   = "updatedAt"
+)
+
+var sortFields = map[string]bool{
+	SortName:        true,
+	SortGroup:       true,
+	SortReleaseDate: true,
+	SortID:          true,
+	SortCreatedAt:   true,
+	SortUpdatedAt:   true,
+}
+
+// SortKey is one field of songs order, songs without release date are always placed after songs with it
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultSort orders songs by id
+var DefaultSort = []SortKey{{Field: SortID}}
+
+// ParseSort reads comma separated fields with optional "-" for descending order, e.g. "-releaseDate,group,song".
+// Fields are checked against whitelist and id is appended when it is absent, so the order is always total
+func ParseSort(value string) ([]SortKey, error) {
+
+	if strings.TrimSpace(value) == "" {
+		return DefaultSort, nil
+	}
+
+	keys := make([]SortKey, 0)
+	used := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !sortFields[key.Field] {
+			return nil, NewValidationError("sort", "unknown field [%s], use: song, group, releaseDate, id, createdAt, updatedAt", part)
+		}
+		if used[key.Field] {
+			return nil, NewValidationError("sort", "field [%s] is used twice", key.Field)
+		}
+		used[key.Field] = true
+		keys = append(keys, key)
+	}
+
+	//id is unique, fields after it do not change the order
+	for i, key := range keys {
+		if key.Field == SortID {
+			return keys[:i+1], nil
+		}
+	}
+
+	return append(keys, SortKey{Field: SortID}), nil
+}
+
+// FormatSort is the inverse of ParseSort
+func FormatSort(keys []SortKey) string {
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.Desc {
+			parts = append(parts, "-"+key.Field)
+		} else {
+			parts = append(parts, key.Field)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// SortValue returns value of song field as it is kept in cursor, nil for song without release date.
+// Release date has format dd.mm.yyyy, times are in RFC 3339 format
+func SortValue(item *Song, field string) *string {
+
+	var value string
+	switch field {
+	case SortName:
+		value = item.Name
+	case SortGroup:
+		value = item.Group
+	case SortReleaseDate:
+		if item.ReleaseDate == "" {
+			return nil
+		}
+		value = item.ReleaseDate
+	case SortID:
+		value = strconv.Itoa(item.ID)
+	case SortCreatedAt:
+		value = item.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortUpdatedAt:
+		value = item.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return &value
+}
+
+// SortKeys returns order of filter, songs are ordered by id when sort is not set
+func (f Filter) SortKeys() []SortKey {
+
+	if len(f.Sort) == 0 {
+		return DefaultSort
+	}
+	return f.Sort
+}
@@ -0,0 +1,53 @@
+package song
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// TrashedSong model info
+// @Description deleted song kept in trash until purge
+type TrashedSong struct {
+	Song
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// Trash is implemented by storages which keep deleted songs until purge, Storage.Delete moves song to trash
+// and trashed songs are not returned or changed by other methods
+type Trash interface {
+	//GetTrash returns trashed songs, the latest deleted first
+	GetTrash(limit, offset int) ([]*TrashedSong, error)
+	//RestoreTrashed takes song out of trash, ErrConflict means song with the same name and group was added after delete
+	RestoreTrashed(id int) error
+	//Purge deletes trashed song permanently
+	Purge(id int) error
+	//PurgeTrash deletes permanently songs trashed before deletedBefore and returns their number
+	PurgeTrash(deletedBefore time.Time) (int, error)
+}
+
+// TrashPurger deletes permanently songs kept in trash longer than Retention, trash is checked every Interval
+type TrashPurger struct {
+	Storage   Trash
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// Run purges trash until ctx is done
+func (p *TrashPurger) Run(ctx context.Context) {
+
+	for {
+		purged, err := p.Storage.PurgeTrash(time.Now().Add(-p.Retention))
+		if err != nil {
+			log.Printf("trash purge error: [%s]\n", err.Error())
+		} else if purged > 0 {
+			log.Printf("trash purged, songs: [%d], retention: [%s]\n", purged, p.Retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.Interval):
+		}
+	}
+}
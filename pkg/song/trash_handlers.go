@@ -0,0 +1,120 @@
+package song
+
+import (
+	"log"
+	"net/http"
+)
+
+// @Summary Get trash
+// @Description Get deleted songs, the latest deleted first. Deleted songs are kept in trash until purge, they are not shown in songs list and names of them can be used by new songs
+// @Tags trash
+// @ID get-trash
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Produce json
+// @Success 200 {object} song.Response{response=[]song.TrashedSong}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/trash [get]
+func (sh *SongHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+
+	trash, ok := sh.Storage.(Trash)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	limit, offset, err := ReadPagination(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	songs, err := trash.GetTrash(limit, offset)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": songs,
+	})
+}
+
+// @Summary Restore song from trash
+// @Description Take deleted song out of trash, restore is recorded as revision. Song cannot be restored when song with the same name and group was added after delete
+// @Tags trash
+// @ID restore-trashed
+// @Param id path int true "song id"
+// @Param X-Actor header string false "who restores song, recorded in revision" Default(anonymous)
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/trash/{id}/restore [post]
+func (sh *SongHandler) RestoreTrashed(w http.ResponseWriter, r *http.Request) {
+
+	if _, ok := sh.Storage.(Trash); !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	storage, err := sh.actorStorage(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err = storage.(Trash).RestoreTrashed(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("song restored from trash, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+// @Summary Purge song
+// @Description Delete song from trash permanently with its verses, tags and album tracks, revisions of song are kept
+// @Tags trash
+// @ID purge
+// @Param id path int true "song id"
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/trash/{id} [delete]
+func (sh *SongHandler) Purge(w http.ResponseWriter, r *http.Request) {
+
+	trash, ok := sh.Storage.(Trash)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err = trash.Purge(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("song purged, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
@@ -0,0 +1,333 @@
+package song
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// V2Handler serves songs under /api/v2 with standard verbs, song id is taken from path instead of body
+type V2Handler struct {
+	*SongHandler
+}
+
+// songLocation is path of song resource in /api/v2
+func songLocation(id int) string {
+	return fmt.Sprintf("/api/v2/songs/%d", id)
+}
+
+// @Summary Get song
+// @Description Get song with all its fields
+// @Tags songs v2
+// @ID v2-get
+// @Param id path int true "song id"
+// @Param If-None-Match header string false "ETag of song from previous answer, 304 is sent when song is not changed"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Song}
+// @Header 200 {string} ETag "version of song"
+// @Success 304 "song is not changed"
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/v2/songs/{id} [get]
+func (vh *V2Handler) Get(w http.ResponseWriter, r *http.Request) {
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	item, err := vh.Storage.GetSong(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", item.ETag)
+	if notModified(w, r, item) {
+		return
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": item,
+	})
+}
+
+// @Summary Add new song
+// @Description Add new song, its path is returned in Location header. When release date, text and link are all empty, they are filled by enrichment job like in PUT /api/songs
+// @Tags songs v2
+// @ID v2-create
+// @Param bodyJSON body string true "song and group are required" SchemaExample({"song":"Supermassive Black Hole","group":"Muse","releaseDate":"16.07.2006","text":"some text","link":"some link"})
+// @Param X-Actor header string false "who adds song, recorded in revision" Default(anonymous)
+// @Produce json
+// @Success 201 {object} song.Response{response=song.Song,enrichment=string}
+// @Header 201 {string} Location "path of new song"
+// @Header 201 {string} ETag "version of song"
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/v2/songs [post]
+func (vh *V2Handler) New(w http.ResponseWriter, r *http.Request) {
+
+	data := &Song{}
+	err := ReadJSON(r, data)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	if data.Name == "" || data.Group == "" {
+		WriteError(w, r, NewValidationError("", "song and group values must be not empty"))
+		return
+	}
+
+	storage, err := vh.actorStorage(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	var id int
+	response := Response{}
+	if queue, ok := storage.(EnrichmentQueue); ok && data.ReleaseDate == "" && data.Text == "" && data.Link == "" {
+		var enrichment *Enrichment
+		id, enrichment, err = queue.AddWithEnrichment(data.Name, data.Group, "", "", "")
+		if err == nil {
+			response["enrichment"] = enrichment.Status
+		}
+	} else {
+		id, err = storage.Add(data.Name, data.Group, data.ReleaseDate, data.Text, data.Link)
+	}
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	item, err := vh.Storage.GetSong(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	response["response"] = item
+
+	w.Header().Set("Location", songLocation(id))
+	w.Header().Set("ETag", item.ETag)
+	WriteResponse(w, r, http.StatusCreated, response)
+}
+
+// @Summary Replace song data
+// @Description Replace release date, text and link of song with sent ones, empty or missing field is cleared. Song and group may be sent,
+// @Description but they must be equal to stored ones, use PATCH to change them
+// @Tags songs v2
+// @ID v2-replace
+// @Param id path int true "song id"
+// @Param bodyJSON body string true "release date, text and link" SchemaExample({"releaseDate":"16.07.2006","text":"some text","link":""})
+// @Param X-Actor header string false "who changes song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is changed only when it is not changed by others since read"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Song}
+// @Header 200 {string} ETag "version of song"
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/v2/songs/{id} [put]
+func (vh *V2Handler) Replace(w http.ResponseWriter, r *http.Request) {
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	data := &Song{}
+	err = ReadJSON(r, data)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	//preconditions are checked first, so stale or missing If-Match is reported before the body
+	storage, stored, err := vh.checkedSongStorage(r, id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	//song and group are changed only by PATCH, groups which differ only in case are the same group
+	if data.Name != "" || data.Group != "" {
+		if stored == nil {
+			stored, err = vh.Storage.GetSong(id)
+			if err != nil {
+				WriteError(w, r, err)
+				return
+			}
+		}
+		if data.Name != "" && data.Name != stored.Name || data.Group != "" && !strings.EqualFold(data.Group, stored.Group) {
+			WriteError(w, r, NewValidationError("", "song and group cannot be changed by PUT, use PATCH"))
+			return
+		}
+	}
+
+	//Update keeps stored values instead of empty ones, so replace is applied as patch with all fields set
+	patcher, ok := storage.(Patcher)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	err = patcher.Patch(id, &SongPatch{
+		ReleaseDate: &data.ReleaseDate,
+		Text:        &data.Text,
+		Link:        &data.Link,
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("song updated, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	vh.writeSong(w, r, id)
+}
+
+// @Summary Patch song
+// @Description Change song with JSON merge patch (RFC 7396): only sent fields are changed, null clears release date, text or link.
+// @Description Song and group can be changed, but not cleared, pair of them must stay unique. Unknown and read-only fields are rejected
+// @Tags songs v2
+// @ID v2-patch
+// @Param id path int true "song id"
+// @Param bodyJSON body string true "merge patch with any of fields song, group, releaseDate, text, link" SchemaExample({"group":"Muse","link":null})
+// @Param X-Actor header string false "who changes song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is changed only when it is not changed by others since read"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Song}
+// @Header 200 {string} ETag "version of song"
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 409 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Failure 501 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Router /api/v2/songs/{id} [patch]
+func (vh *V2Handler) Patch(w http.ResponseWriter, r *http.Request) {
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("request body read error: [%s], user agent: [%s], path: [%s], method: [%s]\n", err.Error(), r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+		WriteError(w, r, NewValidationError("body", "request body read error"))
+		return
+	}
+
+	patch, err := ParseMergePatch(body)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	storage, err := vh.songStorage(r, id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	patcher, ok := storage.(Patcher)
+	if !ok {
+		WriteError(w, r, ErrNotSupported)
+		return
+	}
+
+	err = patcher.Patch(id, patch)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("song patched, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	vh.writeSong(w, r, id)
+}
+
+// writeSong answers with song after change
+func (vh *V2Handler) writeSong(w http.ResponseWriter, r *http.Request, id int) {
+
+	item, err := vh.Storage.GetSong(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", item.ETag)
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": item,
+	})
+}
+
+// @Summary Delete song
+// @Description Move song to trash like DELETE /api/songs
+// @Tags songs v2
+// @ID v2-delete
+// @Param id path int true "song id"
+// @Param X-Actor header string false "who deletes song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is changed only when it is not changed by others since read"
+// @Success 204
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/v2/songs/{id} [delete]
+func (vh *V2Handler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	id, err := ReadPathID(r, "id")
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	storage, err := vh.songStorage(r, id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err = storage.Delete(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	log.Printf("song deleted, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+// Deprecated adds headers of deprecated endpoint to answers of h: Deprecation with time since which it is deprecated,
+// Sunset with time after which it is removed and Link to successor endpoint. {id} in successor is replaced with id
+// from path of request, successor without it is used when request has no id in path
+func Deprecated(h http.HandlerFunc, since, sunset time.Time, successor string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		link := successor
+		if id := r.PathValue("id"); id != "" {
+			link = strings.ReplaceAll(link, "{id}", url.PathEscape(id))
+		} else {
+			link = strings.ReplaceAll(link, "/{id}", "")
+		}
+
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", since.Unix()))
+		w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", link))
+		h(w, r)
+	}
+}
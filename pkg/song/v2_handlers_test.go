@@ -0,0 +1,145 @@
+package song_test
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplaceClearsFields(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		want     song.Song
+	}{
+		{
+			name:     "all fields",
+			body:     `{"releaseDate":"2009","text":"new text","link":"https://example.com/new"}`,
+			wantCode: http.StatusOK,
+			want:     song.Song{ReleaseDate: "2009", Text: "new text", Link: "https://example.com/new"},
+		},
+		{
+			name:     "empty and missing fields are cleared",
+			body:     `{"song":"Uprising","releaseDate":"","text":"new text"}`,
+			wantCode: http.StatusOK,
+			want:     song.Song{Text: "new text"},
+		},
+		{
+			name:     "song is not changed",
+			body:     `{"song":"Starlight","releaseDate":"2009","text":"new text","link":""}`,
+			wantCode: http.StatusBadRequest,
+			want:     song.Song{ReleaseDate: "07.09.2009", Text: "old text", Link: "https://example.com/old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			memory := storage.NewMemoryStorage()
+			id, err := memory.Add("Uprising", "Muse", "07.09.2009", "old text", "https://example.com/old")
+			if err != nil {
+				t.Fatalf("add error: %v", err)
+			}
+			vh := &song.V2Handler{SongHandler: &song.SongHandler{Storage: memory}}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v2/songs/"+strconv.Itoa(id), strings.NewReader(tt.body))
+			req.SetPathValue("id", strconv.Itoa(id))
+			rec := httptest.NewRecorder()
+			vh.Replace(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status is %d, want %d, body: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			item, err := memory.GetSong(id)
+			if err != nil {
+				t.Fatalf("get song error: %v", err)
+			}
+			if item.ReleaseDate != tt.want.ReleaseDate || item.Text != tt.want.Text || item.Link != tt.want.Link {
+				t.Errorf("song has release date %q, text %q and link %q, want %q, %q and %q",
+					item.ReleaseDate, item.Text, item.Link, tt.want.ReleaseDate, tt.want.Text, tt.want.Link)
+			}
+		})
+	}
+}
+
+// TestReplacePreconditions checks that If-Match is checked before song and group of body
+func TestReplacePreconditions(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		body     string
+		ifMatch  string
+		wantCode int
+	}{
+		{"missing If-Match", `{"song":"Starlight","text":"new text"}`, "", http.StatusPreconditionRequired},
+		{"stale If-Match", `{"song":"Starlight","text":"new text"}`, `"stale"`, http.StatusPreconditionFailed},
+		{"song is changed", `{"song":"Starlight","text":"new text"}`, "current", http.StatusBadRequest},
+		{"group in other case", `{"song":"Uprising","group":"muse","text":"new text"}`, "current", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			memory := storage.NewMemoryStorage()
+			id, err := memory.Add("Uprising", "Muse", "", "old text", "")
+			if err != nil {
+				t.Fatalf("add error: %v", err)
+			}
+			stored, err := memory.GetSong(id)
+			if err != nil {
+				t.Fatalf("get song error: %v", err)
+			}
+			vh := &song.V2Handler{SongHandler: &song.SongHandler{Storage: memory, RequireIfMatch: true}}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v2/songs/"+strconv.Itoa(id), strings.NewReader(tt.body))
+			req.SetPathValue("id", strconv.Itoa(id))
+			if tt.ifMatch == "current" {
+				req.Header.Set("If-Match", stored.ETag)
+			} else if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			vh.Replace(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("status is %d, want %d, body: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeprecatedSuccessor(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		pattern   string
+		path      string
+		successor string
+		want      string
+	}{
+		{"id in path", "GET /api/songs/{id}", "/api/songs/7", "/api/v2/songs/{id}", "</api/v2/songs/7>; rel=\"successor-version\""},
+		{"id in body", "DELETE /api/songs", "/api/songs", "/api/v2/songs/{id}", "</api/v2/songs>; rel=\"successor-version\""},
+		{"collection", "GET /api/songs", "/api/songs", "/api/v2/songs", "</api/v2/songs>; rel=\"successor-version\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, song.Deprecated(func(w http.ResponseWriter, r *http.Request) {}, time.Now(), time.Now(), tt.successor))
+
+			method, _, _ := strings.Cut(tt.pattern, " ")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(method, tt.path, nil))
+			if got := rec.Header().Get("Link"); got != tt.want {
+				t.Errorf("Link is %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
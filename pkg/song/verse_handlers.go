@@ -0,0 +1,122 @@
+package song
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Get verse
+// @Description Get single verse of song by position
+// @Tags songs
+// @ID get-verse
+// @Param id path int true "song id"
+// @Param position path int true "verse position, starts from 1"
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Verse}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/songs/{id}/verses/{position} [get]
+func (sh *SongHandler) GetVerse(w http.ResponseWriter, r *http.Request) {
+
+	id, position, err := readVersePath(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	verse, err := sh.Storage.GetVerse(id, position)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": verse,
+	})
+}
+
+// @Summary Edit verse
+// @Description Replace text of single verse, label like [Chorus] on the first line or kind sets verse kind, empty kind keeps the current one
+// @Tags songs
+// @ID update-verse
+// @Param id path int true "song id"
+// @Param position path int true "verse position, starts from 1"
+// @Param X-Actor header string false "who changes song, recorded in revision" Default(anonymous)
+// @Param If-Match header string false "ETag of song, song is changed only when it is not changed by others since read"
+// @Param bodyJSON body string true "text is required, text cannot contain empty lines, kind is verse, chorus, bridge or intro" SchemaExample({"kind":"chorus","text":"line1\nline2"})
+// @Accept json
+// @Produce json
+// @Success 200 {object} song.Response{response=song.Verse}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 412 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 428 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/songs/{id}/verses/{position} [post]
+func (sh *SongHandler) UpdateVerse(w http.ResponseWriter, r *http.Request) {
+
+	id, position, err := readVersePath(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	verse := &Verse{}
+	err = ReadJSON(r, verse)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	if verse.Kind != "" && !IsVerseKind(verse.Kind) {
+		WriteError(w, r, NewValidationError("kind", "must be verse, chorus, bridge or intro"))
+		return
+	}
+
+	//text is parsed as song text, so stored verse looks the same as verses written with the whole song
+	parsed := ParseVerses(verse.Text)
+	if len(parsed) != 1 {
+		WriteError(w, r, NewValidationError("text", "must be one verse without empty lines"))
+		return
+	}
+
+	verse.Position = position
+	verse.Text = parsed[0].Text
+	if verse.Kind == "" && parsed[0].Kind != VerseKindVerse {
+		verse.Kind = parsed[0].Kind
+	}
+
+	storage, err := sh.songStorage(r, id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err = storage.UpdateVerse(id, verse)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("verse updated, id: [%d], position: [%d], user agent: [%s], path: [%s], method: [%s]\n", id, position, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+	WriteResponse(w, r, http.StatusOK, Response{
+		"response": verse,
+	})
+}
+
+func readVersePath(r *http.Request) (id, position int, err error) {
+
+	id, err = ReadPathID(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	position, err = strconv.Atoi(r.PathValue("position"))
+	if err != nil || position < 1 {
+		return 0, 0, NewValidationError("position", "must be positive number")
+	}
+
+	return id, position, nil
+}
@@ -0,0 +1,131 @@
+package song
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	VerseKindVerse  = "verse"
+	VerseKindChorus = "chorus"
+	VerseKindBridge = "bridge"
+	VerseKindIntro  = "intro"
+)
+
+// Verse model info
+// @Description part of song text, position starts from 1
+type Verse struct {
+	Position int    `json:"position"`
+	Kind     string `json:"kind"`
+	Text     string `json:"text"`
+}
+
+// labelKinds maps beginning of labels like "[Chorus]" or "[Verse 2]" to verse kinds,
+// unknown labels are treated as verse
+var labelKinds = []struct {
+	prefix string
+	kind   string
+}{
+	{"chorus", VerseKindChorus},
+	{"refrain", VerseKindChorus},
+	{"hook", VerseKindChorus},
+	{"bridge", VerseKindBridge},
+	{"intro", VerseKindIntro},
+}
+
+// IsVerseKind reports whether kind is one of known verse kinds
+func IsVerseKind(kind string) bool {
+	switch kind {
+	case VerseKindVerse, VerseKindChorus, VerseKindBridge, VerseKindIntro:
+		return true
+	}
+	return false
+}
+
+// ParseVerses splits lyrics to verses by empty lines, line endings are normalized and trailing spaces are removed,
+// label in square brackets on the first line of verse sets its kind and is not kept in verse text
+func ParseVerses(text string) []*Verse {
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	verses := make([]*Verse, 0)
+	kind := ""
+	lines := make([]string, 0)
+
+	flush := func() {
+		if len(lines) == 0 {
+			return
+		}
+		if kind == "" {
+			kind = VerseKindVerse
+		}
+		verses = append(verses, &Verse{
+			Position: len(verses) + 1,
+			Kind:     kind,
+			Text:     strings.Join(lines, "\n"),
+		})
+		kind = ""
+		lines = lines[:0]
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		//label of verse is on its own line, label followed by empty line is applied to the next verse
+		if len(lines) == 0 {
+			if label, ok := verseLabel(line); ok {
+				kind = labelKind(label)
+				continue
+			}
+		}
+
+		lines = append(lines, line)
+	}
+	flush()
+
+	return verses
+}
+
+// JoinVerses builds song text from verses, kinds other than verse are written as labels,
+// so ParseVerses of the result gives the same verses
+func JoinVerses(verses []*Verse) string {
+
+	parts := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		if verse.Kind == VerseKindVerse || verse.Kind == "" {
+			parts = append(parts, verse.Text)
+			continue
+		}
+		parts = append(parts, "["+strings.ToUpper(verse.Kind[:1])+verse.Kind[1:]+"]\n"+verse.Text)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func verseLabel(line string) (string, bool) {
+
+	line = strings.TrimSpace(line)
+	if len(line) < 2 || line[0] != '[' || line[len(line)-1] != ']' {
+		return "", false
+	}
+
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+func labelKind(label string) string {
+
+	label = strings.ToLower(label)
+	for _, item := range labelKinds {
+		if strings.HasPrefix(label, item.prefix) {
+			return item.kind
+		}
+	}
+
+	return VerseKindVerse
+}
@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"SongLibrary/pkg/album"
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+func errAlbumNotFound(id int) error {
+	return fmt.Errorf("album with id [%d]: %w", id, song.ErrNotFound)
+}
+
+func (s *Storage) AddAlbum(item *album.Album) (int, error) {
+
+	date, precision, err := parseReleaseDate(item.ReleaseDate)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method add album begin transaction error: [%s]\n", err.Error())
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	groupID, err := upsertGroup(tx, item.Group)
+	if err != nil {
+		return 0, err
+	}
+
+	var insertID int
+	err = tx.QueryRow(
+		`INSERT INTO albums(title,group_id,release_date,release_date_precision,cover_link)
+	VALUES($1,$2,$3,$4,NULLIF($5,''))
+	RETURNING id`,
+		item.Title, groupID, date, precision, item.CoverLink,
+	).Scan(&insertID)
+
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("album [%s] of group [%s]", item.Title, item.Group))
+		if !isMapped(err) {
+			log.Printf("method add album query error: [%s], args: [title: %s, group: %s]\n", err.Error(), item.Title, item.Group)
+		}
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method add album commit error: [%s]\n", err.Error())
+		return 0, err
+	}
+
+	return insertID, nil
+}
+
+func (s *Storage) DeleteAlbum(id int) error {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM albums WHERE id = $1`, id,
+	)
+	if err != nil {
+		log.Printf("method delete album query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errAlbumNotFound(id)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetAlbum(id int) (*album.Album, error) {
+
+	item, err := scanAlbum(s.DB.QueryRow(
+		`SELECT albums.id, title, groups.name, release_date, release_date_precision, cover_link
+	FROM albums JOIN groups ON groups.id = albums.group_id
+	WHERE albums.id = $1`, id,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errAlbumNotFound(id)
+		}
+		log.Printf("method get album query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT songs.id, songs.song_name, groups.name, album_songs.disc_number, album_songs.track_number
+	FROM album_songs
+	JOIN songs ON songs.id = album_songs.song_id
+	JOIN groups ON groups.id = songs.group_id
+	WHERE album_songs.album_id = $1 AND songs.deleted_at IS NULL
+	ORDER BY album_songs.disc_number, album_songs.track_number`, id,
+	)
+	if err != nil {
+		log.Printf("method get album tracks query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	item.Tracks = make([]*album.Track, 0)
+	for rows.Next() {
+		track := &album.Track{}
+		err := rows.Scan(&track.SongID, &track.Song, &track.Group, &track.Disc, &track.Number)
+		if err != nil {
+			log.Printf("method get album tracks scan error: [%s], id: [%d]\n", err.Error(), id)
+			return nil, err
+		}
+		item.Tracks = append(item.Tracks, track)
+	}
+
+	return item, rows.Err()
+}
+
+func (s *Storage) GetAlbums(limit, offset int, groupFragment string) ([]*album.Album, error) {
+
+	query := "SELECT albums.id, title, groups.name, release_date, release_date_precision, cover_link FROM albums JOIN groups ON groups.id = albums.group_id "
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+
+	if groupFragment != "" {
+		query += fmt.Sprintf("WHERE groups.name ILIKE CONCAT('%%',$%d::text,'%%') ", placeholderNum)
+		placeholderNum++
+		args = append(args, groupFragment)
+	}
+
+	query += "ORDER BY albums.id "
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get albums query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var albums []*album.Album
+	for rows.Next() {
+		item, err := scanAlbum(rows)
+		if err != nil {
+			log.Printf("method get albums scan error: [%s], query: [%s]\n", err.Error(), query)
+			return nil, err
+		}
+		albums = append(albums, item)
+	}
+
+	return albums, rows.Err()
+}
+
+// AttachSong puts song to album, zero disc means the first disc and zero track number means the next free one,
+// track gets the resulting position
+func (s *Storage) AttachSong(albumID int, track *album.Track) error {
+
+	if track.Disc == 0 {
+		track.Disc = 1
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method attach song begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	//songs in trash are not attached, song is locked, so it is not moved to trash before commit
+	var exists bool
+	err = tx.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL FOR SHARE)`, track.SongID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("method attach song song query error: [%s], song id: [%d]\n", err.Error(), track.SongID)
+		return err
+	}
+	if !exists {
+		return errSongNotFound(track.SongID)
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO album_songs(album_id,song_id,disc_number,track_number)
+	SELECT $1::integer, $2::integer, $3::integer, CASE WHEN $4::integer > 0 THEN $4::integer ELSE coalesce(max(track_number), 0) + 1 END
+	FROM album_songs WHERE album_id = $1::integer AND disc_number = $3::integer
+	RETURNING track_number`,
+		albumID, track.SongID, track.Disc, track.Number,
+	).Scan(&track.Number)
+
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("song with id [%d] on album with id [%d]", track.SongID, albumID))
+		if !isMapped(err) {
+			log.Printf("method attach song query error: [%s], album id: [%d], song id: [%d]\n", err.Error(), albumID, track.SongID)
+		}
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("song with id [%d] on album with id [%d]", track.SongID, albumID))
+		if !isMapped(err) {
+			log.Printf("method attach song commit error: [%s], album id: [%d], song id: [%d]\n", err.Error(), albumID, track.SongID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) DetachSong(albumID, songID int) error {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM album_songs WHERE album_id = $1 AND song_id = $2`, albumID, songID,
+	)
+	if err != nil {
+		log.Printf("method detach song query error: [%s], album id: [%d], song id: [%d]\n", err.Error(), albumID, songID)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return fmt.Errorf("song with id [%d] on album with id [%d]: %w", songID, albumID, song.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ReorderTracks moves listed songs to new positions, uniqueness of positions is checked on commit
+func (s *Storage) ReorderTracks(albumID int, tracks []*album.Track) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method reorder tracks begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`SET CONSTRAINTS album_songs_position_key DEFERRED`)
+	if err != nil {
+		log.Printf("method reorder tracks set constraints error: [%s]\n", err.Error())
+		return err
+	}
+
+	for _, track := range tracks {
+		result, err := tx.Exec(
+			`UPDATE album_songs SET disc_number = $1, track_number = $2 WHERE album_id = $3 AND song_id = $4`,
+			track.Disc, track.Number, albumID, track.SongID,
+		)
+		if err != nil {
+			err = pqError(err, fmt.Sprintf("track of song with id [%d]", track.SongID))
+			if !isMapped(err) {
+				log.Printf("method reorder tracks query error: [%s], album id: [%d], song id: [%d]\n", err.Error(), albumID, track.SongID)
+			}
+			return err
+		}
+
+		num, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if num == 0 {
+			return fmt.Errorf("song with id [%d] on album with id [%d]: %w", track.SongID, albumID, song.ErrNotFound)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("track positions on album with id [%d]", albumID))
+		if !isMapped(err) {
+			log.Printf("method reorder tracks commit error: [%s], album id: [%d]\n", err.Error(), albumID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAlbum(row rowScanner) (*album.Album, error) {
+
+	var date sql.NullTime
+	var precision string
+	var coverLink sql.NullString
+	item := &album.Album{}
+
+	err := row.Scan(&item.ID, &item.Title, &item.Group, &date, &precision, &coverLink)
+	if err != nil {
+		return nil, err
+	}
+
+	if date.Valid {
+		item.ReleaseDate = song.NewReleaseDate(date.Time, precision).String()
+	}
+	item.CoverLink = coverLink.String
+
+	return item, nil
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"SongLibrary/pkg/enrich"
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+func errEnrichmentNotFound(songID int) error {
+	return fmt.Errorf("enrichment of song with id [%d]: %w", songID, song.ErrNotFound)
+}
+
+// rowQuerier is *sql.DB or *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (s *Storage) EnqueueEnrichment(songID int) (*song.Enrichment, error) {
+	return enqueueEnrichment(s.DB, songID)
+}
+
+// AddWithEnrichment adds song and its pending enrichment job in one transaction
+func (s *Storage) AddWithEnrichment(name, group, releaseDate, text, link string) (int, *song.Enrichment, error) {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method add with enrichment begin transaction error: [%s]\n", err.Error())
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	insertID, err := s.addSong(tx, name, group, releaseDate, text, link)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	enrichment, err := enqueueEnrichment(tx, insertID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method add with enrichment commit error: [%s]\n", err.Error())
+		return 0, nil, err
+	}
+
+	return insertID, enrichment, nil
+}
+
+func enqueueEnrichment(q rowQuerier, songID int) (*song.Enrichment, error) {
+
+	enrichment, err := scanEnrichment(q.QueryRow(
+		`INSERT INTO enrichment_jobs(song_id) SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL
+	ON CONFLICT (song_id) DO UPDATE SET status = 'pending', attempts = 0, last_error = NULL, next_run_at = now(), updated_at = now()
+	RETURNING song_id, status, attempts, last_error, next_run_at, updated_at`, songID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errSongNotFound(songID)
+	}
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("song with id [%d]", songID))
+		if !isMapped(err) {
+			log.Printf("method enqueue enrichment query error: [%s], song id: [%d]\n", err.Error(), songID)
+		}
+		return nil, err
+	}
+
+	return enrichment, nil
+}
+
+func (s *Storage) GetEnrichment(songID int) (*song.Enrichment, error) {
+
+	enrichment, err := scanEnrichment(s.DB.QueryRow(
+		`SELECT song_id, status, attempts, last_error, next_run_at, updated_at FROM enrichment_jobs WHERE song_id = $1`, songID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errEnrichmentNotFound(songID)
+		}
+		log.Printf("method get enrichment query error: [%s], song id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	return enrichment, nil
+}
+
+// ClaimEnrichment uses SKIP LOCKED, so several workers never take the same job
+func (s *Storage) ClaimEnrichment(staleAfter time.Duration) (*enrich.Job, error) {
+
+	job := &enrich.Job{}
+	err := s.DB.QueryRow(
+		`WITH claimed AS (
+		UPDATE enrichment_jobs SET status = 'running', attempts = attempts + 1, updated_at = now()
+		WHERE song_id = (
+			SELECT song_id FROM enrichment_jobs
+			WHERE (status = 'pending' AND next_run_at <= now())
+			OR (status = 'running' AND updated_at <= now() - $1 * interval '1 millisecond')
+			ORDER BY next_run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING song_id, attempts
+	)
+	SELECT claimed.song_id, songs.song_name, groups.name, claimed.attempts
+	FROM claimed
+	JOIN songs ON songs.id = claimed.song_id
+	JOIN groups ON groups.id = songs.group_id`,
+		staleAfter.Milliseconds(),
+	).Scan(&job.SongID, &job.Song, &job.Group, &job.Attempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("method claim enrichment query error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *Storage) CompleteEnrichment(songID int) error {
+
+	err := s.finishEnrichment(songID, song.EnrichmentDone, time.Now(), "")
+	if err != nil {
+		return err
+	}
+
+	return s.MarkEnriched(songID)
+}
+
+func (s *Storage) RetryEnrichment(songID int, nextRunAt time.Time, reason string) error {
+	return s.finishEnrichment(songID, song.EnrichmentPending, nextRunAt, reason)
+}
+
+func (s *Storage) FailEnrichment(songID int, reason string) error {
+	return s.finishEnrichment(songID, song.EnrichmentFailed, time.Now(), reason)
+}
+
+// finishEnrichment saves result of running job, job of deleted song is already deleted, so missing row is not an error
+func (s *Storage) finishEnrichment(songID int, status string, nextRunAt time.Time, reason string) error {
+
+	_, err := s.DB.Exec(
+		`UPDATE enrichment_jobs SET status = $1, next_run_at = $2, last_error = NULLIF($3, ''), updated_at = now()
+	WHERE song_id = $4 AND status = 'running'`,
+		status, nextRunAt, reason, songID,
+	)
+	if err != nil {
+		log.Printf("method finish enrichment query error: [%s], song id: [%d], status: [%s]\n", err.Error(), songID, status)
+		return err
+	}
+
+	return nil
+}
+
+func scanEnrichment(row rowScanner) (*song.Enrichment, error) {
+
+	var lastError sql.NullString
+	enrichment := &song.Enrichment{}
+
+	err := row.Scan(&enrichment.SongID, &enrichment.Status, &enrichment.Attempts, &lastError, &enrichment.NextRunAt, &enrichment.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	enrichment.LastError = lastError.String
+
+	return enrichment, nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var (
+	errBadYear  = song.NewValidationError("releaseDate", "date must have format year")
+	errBadDate  = song.NewValidationError("releaseDate", "date must have format day.month.year, month.year or year")
+	errNoUpdate = song.NewValidationError("", "no data to update, release date or link or text must be not empty")
+)
+
+func errSongNotFound(id int) error {
+	return fmt.Errorf("song with id [%d]: %w", id, song.ErrNotFound)
+}
+
+func errTrashedNotFound(id int) error {
+	return fmt.Errorf("song with id [%d] in trash: %w", id, song.ErrNotFound)
+}
+
+func errVerseNotFound(id, position int) error {
+	return fmt.Errorf("verse [%d] of song with id [%d]: %w", position, id, song.ErrNotFound)
+}
+
+func errRevisionNotFound(songID, revisionID int) error {
+	return fmt.Errorf("revision [%d] of song with id [%d]: %w", revisionID, songID, song.ErrNotFound)
+}
+
+// errRestoreDeleted is returned for revision which deleted song, it has no state to restore
+func errRestoreDeleted(revisionID int) error {
+	return song.NewValidationError("revision", "revision [%d] deleted song, choose revision with song state", revisionID)
+}
+
+// errVersionChanged is returned when song was changed after version expected by If-Match
+func errVersionChanged(id, version int) error {
+	return fmt.Errorf("song with id [%d] has ETag %s: %w", id, song.ETag(version), song.ErrPreconditionFailed)
+}
+
+func errSongExists(name, group string) error {
+	return fmt.Errorf("song [%s] of group [%s]: %w", name, group, song.ErrConflict)
+}
+
+// errUnsupportedFilter is returned by storages without relations needed for some filter fields
+func errUnsupportedFilter(field string) error {
+	return fmt.Errorf("filter by %s: %w", field, song.ErrNotSupported)
+}
+
+// pqError converts postgres error codes to song errors, subject describes the row for message,
+// errors without known code are returned as is
+func pqError(err error, subject string) error {
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case "23505": //unique_violation
+		return fmt.Errorf("%s: %w", subject, song.ErrConflict)
+	case "23503": //foreign_key_violation
+		return fmt.Errorf("%s: referenced row %w", subject, song.ErrNotFound)
+	case "23502": //not_null_violation
+		return song.NewValidationError(pqErr.Column, "must be not empty")
+	case "22001": //string_data_right_truncation
+		return song.NewValidationError(pqErr.Column, "value too long")
+	case "23514": //check_violation
+		return song.NewValidationError(pqErr.Column, "violates check constraint %s", pqErr.Constraint)
+	case "22007", "22008": //invalid_datetime_format, datetime_field_overflow
+		return errBadDate
+	}
+
+	return err
+}
+
+// sqliteError does the same as pqError for sqlite extended result codes
+func sqliteError(err error, subject string) error {
+
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return fmt.Errorf("%s: %w", subject, song.ErrConflict)
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return fmt.Errorf("%s: referenced row %w", subject, song.ErrNotFound)
+	case sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+		return song.NewValidationError("", "%s", sqliteErr.Error())
+	case sqlite3.SQLITE_CONSTRAINT_CHECK:
+		return song.NewValidationError("", "%s", sqliteErr.Error())
+	}
+
+	return err
+}
+
+// isMapped reports whether err is already converted to one of song errors
+func isMapped(err error) bool {
+	return errors.Is(err, song.ErrNotFound) || errors.Is(err, song.ErrConflict) || errors.Is(err, song.ErrValidation)
+}
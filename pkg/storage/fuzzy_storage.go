@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// FindSimilar matches song and group names with pg_trgm word similarity instead of LIKE,
+// other filter fields are applied as in GetAll
+func (s *Storage) FindSimilar(filter song.Filter, threshold float64) ([]*song.SimilarSong, error) {
+
+	if filter.SongName == "" && filter.Group == "" {
+		return nil, song.NewValidationError("", "song or group must be not empty for fuzzy search")
+	}
+
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+	conditions := make([]string, 0)
+	scores := make([]string, 0)
+
+	if filter.SongName != "" {
+		conditions = append(conditions, fmt.Sprintf("$%d <%% song_name", placeholderNum))
+		scores = append(scores, fmt.Sprintf("word_similarity($%d, song_name)", placeholderNum))
+		placeholderNum++
+		args = append(args, filter.SongName)
+	}
+
+	if filter.Group != "" {
+		conditions = append(conditions, fmt.Sprintf("$%d <%% groups.name", placeholderNum))
+		scores = append(scores, fmt.Sprintf("word_similarity($%d, groups.name)", placeholderNum))
+		placeholderNum++
+		args = append(args, filter.Group)
+	}
+
+	filter.SongName, filter.Group = "", ""
+	otherConditions, otherArgs, err := filterConditions(filter, placeholderNum)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, otherConditions...)
+	args = append(args, otherArgs...)
+	placeholderNum += len(otherArgs)
+
+	query := fmt.Sprintf(
+		"SELECT songs.id, song_name, groups.name, release_date, release_date_precision, text, link, songs.created_at, songs.updated_at, songs.version, (%s) / %d AS similarity FROM songs JOIN groups ON groups.id = songs.group_id WHERE %s ORDER BY similarity DESC, songs.id ",
+		strings.Join(scores, " + "), len(scores), strings.Join(conditions, " AND "),
+	)
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, filter.Limit)
+	}
+
+	if filter.Offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, filter.Offset)
+	}
+
+	var songs []*song.SimilarSong
+	err = s.withSimilarityThreshold(threshold, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, args...)
+		if err != nil {
+			log.Printf("method find similar query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var similarity float64
+			item, err := scanSong(rows, &similarity)
+			if err != nil {
+				log.Printf("method find similar scan error: [%s], query: [%s]\n", err.Error(), query)
+				return err
+			}
+			songs = append(songs, &song.SimilarSong{Song: *item, Similarity: similarity})
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return songs, nil
+}
+
+// Suggest returns the most similar existing song and group names, nil means nothing similar found
+func (s *Storage) Suggest(songName, group string, threshold float64) (*song.Suggestion, error) {
+
+	suggestion := &song.Suggestion{}
+	err := s.withSimilarityThreshold(threshold, func(tx *sql.Tx) error {
+		if songName != "" {
+			err := tx.QueryRow(
+				`SELECT song_name FROM songs WHERE $1 <% song_name AND deleted_at IS NULL ORDER BY word_similarity($1, song_name) DESC, song_name LIMIT 1`,
+				songName,
+			).Scan(&suggestion.Song)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				log.Printf("method suggest song query error: [%s], song: [%s]\n", err.Error(), songName)
+				return err
+			}
+		}
+
+		if group != "" {
+			err := tx.QueryRow(
+				`SELECT name FROM groups WHERE $1 <% name ORDER BY word_similarity($1, name) DESC, name LIMIT 1`,
+				group,
+			).Scan(&suggestion.Group)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				log.Printf("method suggest group query error: [%s], group: [%s]\n", err.Error(), group)
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if suggestion.Song == "" && suggestion.Group == "" {
+		return nil, nil
+	}
+
+	return suggestion, nil
+}
+
+// withSimilarityThreshold runs fn in transaction with local pg_trgm threshold,
+// so <% operator uses it and trigram indexes still work
+func (s *Storage) withSimilarityThreshold(threshold float64, fn func(tx *sql.Tx) error) error {
+
+	if threshold <= 0 || threshold > 1 {
+		return song.NewValidationError("threshold", "similarity threshold must be in range (0, 1]")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`,
+		strconv.FormatFloat(threshold, 'f', -1, 64),
+	)
+	if err != nil {
+		log.Printf("set similarity threshold error: [%s], threshold: [%f]\n", err.Error(), threshold)
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
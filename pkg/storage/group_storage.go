@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"SongLibrary/pkg/group"
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+func errGroupNotFound(id int) error {
+	return fmt.Errorf("group with id [%d]: %w", id, song.ErrNotFound)
+}
+
+// upsertGroup returns id of group with the same name ignoring case, group is created if it does not exist
+func upsertGroup(tx *sql.Tx, name string) (int, error) {
+
+	var groupID int
+	err := tx.QueryRow(
+		`INSERT INTO groups(name) VALUES($1)
+	ON CONFLICT ((lower(name))) DO UPDATE SET name = groups.name
+	RETURNING id`,
+		name,
+	).Scan(&groupID)
+
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("group [%s]", name))
+		if !isMapped(err) {
+			log.Printf("upsert group query error: [%s], group: [%s]\n", err.Error(), name)
+		}
+		return 0, err
+	}
+
+	return groupID, nil
+}
+
+func (s *Storage) AddGroup(name string) (int, error) {
+
+	var insertID int
+	err := s.DB.QueryRow(
+		`INSERT INTO groups(name) VALUES($1) RETURNING id`, name,
+	).Scan(&insertID)
+
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("group [%s]", name))
+		if !isMapped(err) {
+			log.Printf("method add group query error: [%s], name: [%s]\n", err.Error(), name)
+		}
+		return 0, err
+	}
+
+	return insertID, nil
+}
+
+// DeleteGroup purges songs of group from trash in the same transaction, they are not counted in group,
+// but keep the group by foreign key
+func (s *Storage) DeleteGroup(id int) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method delete group begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	purged, err := tx.Exec(
+		`DELETE FROM songs WHERE group_id = $1 AND deleted_at IS NOT NULL`, id,
+	)
+	if err != nil {
+		log.Printf("method delete group purge query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	result, err := tx.Exec(
+		`DELETE FROM groups WHERE id = $1`, id,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("group with id [%d] has songs or albums: %w", id, song.ErrConflict)
+		}
+		log.Printf("method delete group query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errGroupNotFound(id)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method delete group commit error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	if num, err := purged.RowsAffected(); err == nil && num > 0 {
+		log.Printf("songs of deleted group are purged from trash, group id: [%d], songs: [%d]\n", id, num)
+	}
+
+	return nil
+}
+
+func (s *Storage) RenameGroup(id int, name string) error {
+
+	result, err := s.DB.Exec(
+		`UPDATE groups SET name = $1 WHERE id = $2`, name, id,
+	)
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("group [%s]", name))
+		if !isMapped(err) {
+			log.Printf("method rename group query error: [%s], id: [%d], name: [%s]\n", err.Error(), id, name)
+		}
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errGroupNotFound(id)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetGroup(id int) (*group.Group, error) {
+
+	item := &group.Group{}
+	err := s.DB.QueryRow(
+		`SELECT groups.id, groups.name, count(songs.id)
+	FROM groups LEFT JOIN songs ON songs.group_id = groups.id AND songs.deleted_at IS NULL
+	WHERE groups.id = $1
+	GROUP BY groups.id`, id,
+	).Scan(&item.ID, &item.Name, &item.SongsCount)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errGroupNotFound(id)
+		}
+		log.Printf("method get group query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *Storage) GetGroups(limit, offset int, nameFragment string) ([]*group.Group, error) {
+
+	query := "SELECT groups.id, groups.name, count(songs.id) FROM groups LEFT JOIN songs ON songs.group_id = groups.id AND songs.deleted_at IS NULL "
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+
+	if nameFragment != "" {
+		query += fmt.Sprintf("WHERE groups.name ILIKE CONCAT('%%',$%d::text,'%%') ", placeholderNum)
+		placeholderNum++
+		args = append(args, nameFragment)
+	}
+
+	query += "GROUP BY groups.id ORDER BY groups.name, groups.id "
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get groups query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*group.Group
+	for rows.Next() {
+		item := &group.Group{}
+		err := rows.Scan(&item.ID, &item.Name, &item.SongsCount)
+		if err != nil {
+			log.Printf("method get groups scan error: [%s], query: [%s]\n", err.Error(), query)
+			return nil, err
+		}
+		groups = append(groups, item)
+	}
+
+	return groups, rows.Err()
+}
+
+func (s *Storage) GetGroupSongs(id, limit, offset int) ([]*song.Song, error) {
+
+	var exists bool
+	err := s.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM groups WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		log.Printf("method get group songs query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+	if !exists {
+		return nil, errGroupNotFound(id)
+	}
+
+	query := `SELECT songs.id, song_name, groups.name, release_date, release_date_precision, text, link, songs.created_at, songs.updated_at, songs.version
+	FROM songs JOIN groups ON groups.id = songs.group_id
+	WHERE songs.group_id = $1 AND songs.deleted_at IS NULL
+	ORDER BY songs.id `
+	placeholderNum := 2
+	args := []interface{}{id}
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get group songs query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var songs []*song.Song
+	for rows.Next() {
+		item, err := scanSong(rows)
+		if err != nil {
+			log.Printf("method get group songs scan error: [%s], query: [%s]\n", err.Error(), query)
+			return nil, err
+		}
+		songs = append(songs, item)
+	}
+
+	return songs, rows.Err()
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ImportSongs uses savepoint for every song, so failed song does not abort transaction of the batch
+func (s *Storage) ImportSongs(songs []*song.Song, onDuplicate string) ([]*song.ImportResult, error) {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method import songs begin transaction error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]*song.ImportResult, 0, len(songs))
+	for _, item := range songs {
+		result := &song.ImportResult{
+			Song:  item.Name,
+			Group: item.Group,
+		}
+		results = append(results, result)
+
+		_, err = tx.Exec(`SAVEPOINT import_song`)
+		if err != nil {
+			log.Printf("method import songs savepoint error: [%s]\n", err.Error())
+			return nil, err
+		}
+
+		err = importSong(tx, item, onDuplicate, s.actor, result)
+		if err != nil {
+			if !isMapped(err) {
+				log.Printf("method import songs query error: [%s], song: [%s], group: [%s]\n", err.Error(), item.Name, item.Group)
+			}
+			result.Status = song.ImportFailed
+			result.ID = 0
+			result.Error = err.Error()
+
+			_, err = tx.Exec(`ROLLBACK TO SAVEPOINT import_song`)
+			if err != nil {
+				log.Printf("method import songs rollback to savepoint error: [%s]\n", err.Error())
+				return nil, err
+			}
+		}
+
+		_, err = tx.Exec(`RELEASE SAVEPOINT import_song`)
+		if err != nil {
+			log.Printf("method import songs release savepoint error: [%s]\n", err.Error())
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method import songs commit error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func importSong(tx *sql.Tx, item *song.Song, onDuplicate, actor string, result *song.ImportResult) error {
+
+	date, precision, err := parseReleaseDate(item.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	groupID, err := upsertGroup(tx, item.Group)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("song [%s] of group [%s]", item.Name, item.Group)
+	err = tx.QueryRow(
+		`INSERT INTO songs(song_name,group_id,release_date,release_date_precision,text,link)
+	VALUES($1,$2,$3,$4,NULLIF($5,''),NULLIF($6,''))
+	ON CONFLICT (song_name, group_id) WHERE deleted_at IS NULL DO NOTHING
+	RETURNING id`,
+		item.Name, groupID, date, precision, item.Text, item.Link,
+	).Scan(&result.ID)
+	if err == nil {
+		result.Status = song.ImportCreated
+		err = replaceVerses(tx, result.ID, item.Text)
+		if err != nil {
+			return err
+		}
+		_, err = recordRevision(tx, result.ID, song.RevisionCreate, actor, nil)
+		return err
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return pqError(err, subject)
+	}
+
+	if onDuplicate == song.DuplicateFail {
+		return errSongExists(item.Name, item.Group)
+	}
+
+	err = tx.QueryRow(`SELECT id FROM songs WHERE song_name = $1 AND group_id = $2 AND deleted_at IS NULL`, item.Name, groupID).Scan(&result.ID)
+	if err != nil {
+		return err
+	}
+
+	//update keeps stored values of empty fields like Update
+	if onDuplicate == song.DuplicateSkip || (!date.Valid && item.Text == "" && item.Link == "") {
+		result.Status = song.ImportSkipped
+		return nil
+	}
+
+	previous, err := songState(tx, result.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE songs SET release_date = coalesce($1, release_date),
+		release_date_precision = CASE WHEN $1 IS NULL THEN release_date_precision ELSE $2 END,
+		text = coalesce(NULLIF($3, ''), text), link = coalesce(NULLIF($4, ''), link)
+	WHERE id = $5`,
+		date, precision, item.Text, item.Link, result.ID,
+	)
+	if err != nil {
+		return pqError(err, subject)
+	}
+
+	if item.Text != "" {
+		err = replaceVerses(tx, result.ID, item.Text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = recordRevision(tx, result.ID, song.RevisionUpdate, actor, previous)
+	if err != nil {
+		return err
+	}
+	result.Status = song.ImportUpdated
+
+	return nil
+}
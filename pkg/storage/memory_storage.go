@@ -0,0 +1,1112 @@
+package storage
+
+import (
+	"SongLibrary/pkg/enrich"
+	"SongLibrary/pkg/song"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memorySong struct {
+	id          int
+	name        string
+	group       string
+	releaseDate song.ReleaseDate
+	text        string
+	link        string
+	verses      []*song.Verse
+	//zero means never enriched
+	lastEnrichedAt time.Time
+	createdAt      time.Time
+	updatedAt      time.Time
+	//version grows with every change like updatedAt
+	version int
+	//zero means song is not in trash
+	deletedAt time.Time
+}
+
+// MemoryStorage keeps songs in process memory, it mirrors behaviour of postgres Storage
+type MemoryStorage struct {
+	*memoryData
+	//actor is recorded in revisions, empty means song.ActorSystem
+	actor string
+	//ifVersion is version song must have to be changed, zero means any version
+	ifVersion int
+}
+
+// memoryData is shared by storages returned by As
+type memoryData struct {
+	mu        *sync.RWMutex
+	songs     []*memorySong
+	nextID    int
+	jobs      map[int]*song.Enrichment
+	runs      []*enrich.RefreshRun
+	revisions []*song.Revision
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		memoryData: &memoryData{
+			mu:        &sync.RWMutex{},
+			songs:     make([]*memorySong, 0),
+			nextID:    1,
+			jobs:      make(map[int]*song.Enrichment),
+			runs:      make([]*enrich.RefreshRun, 0),
+			revisions: make([]*song.Revision, 0),
+		},
+	}
+}
+
+func (s *MemoryStorage) GetAll(filter song.Filter) ([]*song.Song, error) {
+
+	match, err := memoryFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := filter.SortKeys()
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if filter.Cursor != nil {
+		//cursor is checked like in sql storages
+		_, err = cursorValues(filter.Cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	songs := make([]*song.Song, 0)
+	for _, item := range s.songs {
+		if match(item) {
+			songs = append(songs, item.toSong())
+		}
+	}
+
+	//backward page is collected from the end and reversed
+	slices.SortFunc(songs, func(a, b *song.Song) int {
+		return compareSortValues(sortValues(a, keys), sortValues(b, keys), keys)
+	})
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	if filter.Cursor != nil {
+		songs = slices.DeleteFunc(songs, func(item *song.Song) bool {
+			cmp := compareSortValues(sortValues(item, keys), filter.Cursor.Values, keys)
+			return backward && cmp >= 0 || !backward && cmp <= 0
+		})
+	}
+
+	songs = songs[min(filter.Offset, len(songs)):]
+	if filter.Limit > 0 && len(songs) > filter.Limit {
+		songs = songs[:filter.Limit]
+	}
+
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	return songs, nil
+}
+
+func sortValues(item *song.Song, keys []song.SortKey) []*string {
+	values := make([]*string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, song.SortValue(item, key.Field))
+	}
+	return values
+}
+
+// compareSortValues compares values taken by song.SortValue in order of keys, NULL is greater than any value
+// in both directions like in sql storages
+func compareSortValues(a, b []*string, keys []song.SortKey) int {
+
+	for i, key := range keys {
+		var cmp int
+		switch {
+		case a[i] == nil && b[i] == nil:
+			continue
+		case a[i] == nil:
+			return 1
+		case b[i] == nil:
+			return -1
+		}
+
+		switch key.Field {
+		case song.SortReleaseDate:
+			first, _ := song.ParseReleaseDate(*a[i])
+			second, _ := song.ParseReleaseDate(*b[i])
+			cmp = first.Start.Compare(second.Start)
+		case song.SortID:
+			first, _ := strconv.Atoi(*a[i])
+			second, _ := strconv.Atoi(*b[i])
+			cmp = first - second
+		case song.SortCreatedAt, song.SortUpdatedAt:
+			first, _ := time.Parse(time.RFC3339Nano, *a[i])
+			second, _ := time.Parse(time.RFC3339Nano, *b[i])
+			cmp = first.Compare(second)
+		default:
+			cmp = strings.Compare(*a[i], *b[i])
+		}
+
+		if key.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+func (s *MemoryStorage) GetSong(id int) (*song.Song, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item := s.find(id)
+	if item == nil {
+		return nil, errSongNotFound(id)
+	}
+
+	return item.toSong(), nil
+}
+
+func (s *MemoryStorage) CountSongs(filter song.Filter) (int, bool, error) {
+
+	match, err := memoryFilter(filter)
+	if err != nil {
+		return 0, false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, item := range s.songs {
+		if match(item) {
+			count++
+		}
+	}
+
+	return count, false, nil
+}
+
+// memoryFilter returns check of song by filter fields except pagination
+func memoryFilter(filter song.Filter) (func(item *memorySong) bool, error) {
+
+	if filter.AlbumID != 0 {
+		return nil, errUnsupportedFilter("album")
+	}
+
+	if len(filter.Tags) > 0 {
+		return nil, errUnsupportedFilter("tags")
+	}
+
+	from, before, err := releaseBounds(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(item *memorySong) bool {
+		if !item.deletedAt.IsZero() {
+			return false
+		}
+		if filter.SongName != "" && !strings.Contains(item.name, filter.SongName) {
+			return false
+		}
+		if filter.Group != "" && !strings.Contains(item.group, filter.Group) {
+			return false
+		}
+		if (!from.IsZero() || !before.IsZero()) && item.releaseDate.Start.IsZero() {
+			return false
+		}
+		if !from.IsZero() && item.releaseDate.Start.Before(from) {
+			return false
+		}
+		if !before.IsZero() && item.releaseDate.End().After(before) {
+			return false
+		}
+		if filter.Text != "" && !strings.Contains(item.text, filter.Text) {
+			return false
+		}
+		if strings.ToLower(filter.Link) == "true" && item.link == "" {
+			return false
+		}
+		if strings.ToLower(filter.Link) == "false" && item.link != "" {
+			return false
+		}
+		if filter.Query != nil && !matchQuery(filter.Query, item) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// matchQuery evaluates query like queryCondition of sql storages
+func matchQuery(query *song.Query, item *memorySong) bool {
+
+	switch query.Op {
+	case song.QueryAnd:
+		for _, arg := range query.Args {
+			if !matchQuery(arg, item) {
+				return false
+			}
+		}
+		return true
+
+	case song.QueryOr:
+		for _, arg := range query.Args {
+			if matchQuery(arg, item) {
+				return true
+			}
+		}
+		return false
+
+	case song.QueryNot:
+		return !matchQuery(query.Args[0], item)
+	}
+
+	switch query.Field {
+	case song.QuerySong:
+		return strings.Contains(item.name, query.Value)
+	case song.QueryGroup:
+		return strings.Contains(item.group, query.Value)
+	case song.QueryText:
+		return strings.Contains(item.text, query.Value)
+	case song.QueryAny:
+		return strings.Contains(item.name, query.Value) || strings.Contains(item.group, query.Value)
+	case song.QueryLink:
+		return (item.link != "") == (query.Value == "true")
+	case song.QueryReleased:
+		if item.releaseDate.Start.IsZero() {
+			return false
+		}
+		if !query.From.IsZero() && item.releaseDate.Start.Before(query.From) {
+			return false
+		}
+		return query.Before.IsZero() || !item.releaseDate.End().After(query.Before)
+	}
+
+	return false
+}
+
+func (s *MemoryStorage) GetVerses(id, limit, offset int) ([]*song.Verse, int, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item := s.find(id)
+	if item == nil {
+		return nil, 0, errSongNotFound(id)
+	}
+
+	verses := item.verses
+	if offset >= len(verses) {
+		return []*song.Verse{}, len(item.verses), nil
+	}
+	verses = verses[offset:]
+	if limit > 0 && limit < len(verses) {
+		verses = verses[:limit]
+	}
+
+	return copyVerses(verses), len(item.verses), nil
+}
+
+func (s *MemoryStorage) GetVerse(id, position int) (*song.Verse, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item := s.find(id)
+	if item == nil {
+		return nil, errSongNotFound(id)
+	}
+
+	if position < 1 || position > len(item.verses) {
+		return nil, errVerseNotFound(id, position)
+	}
+
+	verse := *item.verses[position-1]
+	return &verse, nil
+}
+
+func (s *MemoryStorage) UpdateVerse(id int, verse *song.Verse) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(id)
+	if item == nil {
+		return errSongNotFound(id)
+	}
+	err := s.checkVersion(item)
+	if err != nil {
+		return err
+	}
+
+	if verse.Position < 1 || verse.Position > len(item.verses) {
+		return errVerseNotFound(id, verse.Position)
+	}
+
+	previous := item.state()
+	stored := item.verses[verse.Position-1]
+	if verse.Kind == "" {
+		verse.Kind = stored.Kind
+	}
+	stored.Kind = verse.Kind
+	stored.Text = verse.Text
+	item.text = song.JoinVerses(item.verses)
+	item.touch(previous)
+	s.record(item.id, song.RevisionUpdate, previous, item.state())
+
+	return nil
+}
+
+func (s *MemoryStorage) Add(name, group, releaseDate, text, link string) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(name, group, releaseDate, text, link)
+}
+
+// add saves new song, it must be called with s.mu held
+func (s *MemoryStorage) add(name, group, releaseDate, text, link string) (int, error) {
+
+	//zero release date means unknown, it is filled later by enrichment
+	var date song.ReleaseDate
+	if releaseDate != "" {
+		var err error
+		date, err = song.ParseReleaseDate(releaseDate)
+		if err != nil {
+			return 0, errBadDate
+		}
+	}
+
+	group = s.groupName(group)
+	for _, item := range s.songs {
+		if item.is(name, group) {
+			return 0, errSongExists(name, group)
+		}
+	}
+
+	item := &memorySong{
+		id:          s.nextID,
+		name:        name,
+		group:       group,
+		releaseDate: date,
+		text:        text,
+		link:        link,
+		verses:      song.ParseVerses(text),
+		createdAt:   time.Now(),
+	}
+	item.updatedAt = item.createdAt
+	item.version = 1
+	s.nextID++
+	s.songs = append(s.songs, item)
+	s.record(item.id, song.RevisionCreate, nil, item.state())
+
+	return item.id, nil
+}
+
+func (s *MemoryStorage) Delete(id int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(id)
+	if item == nil {
+		return errSongNotFound(id)
+	}
+	err := s.checkVersion(item)
+	if err != nil {
+		return err
+	}
+
+	//song is moved to trash, its pending enrichment is dropped
+	item.deletedAt = time.Now()
+	delete(s.jobs, id)
+	s.record(id, song.RevisionDelete, item.state(), nil)
+
+	return nil
+}
+
+func (s *MemoryStorage) Update(id int, releaseDate, text, link string) error {
+
+	var date song.ReleaseDate
+	if releaseDate != "" {
+		var err error
+		date, err = song.ParseReleaseDate(releaseDate)
+		if err != nil {
+			return errBadDate
+		}
+	}
+
+	if releaseDate == "" && text == "" && link == "" {
+		return errNoUpdate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(id)
+	if item == nil {
+		return errSongNotFound(id)
+	}
+	err := s.checkVersion(item)
+	if err != nil {
+		return err
+	}
+
+	previous := item.state()
+	if releaseDate != "" {
+		item.releaseDate = date
+	}
+	if text != "" {
+		item.text = text
+		item.verses = song.ParseVerses(text)
+	}
+	if link != "" {
+		item.link = link
+	}
+	item.touch(previous)
+	s.record(id, song.RevisionUpdate, previous, item.state())
+
+	return nil
+}
+
+func (s *MemoryStorage) Patch(id int, patch *song.SongPatch) error {
+
+	var date song.ReleaseDate
+	if patch.ReleaseDate != nil && *patch.ReleaseDate != "" {
+		var err error
+		date, err = song.ParseReleaseDate(*patch.ReleaseDate)
+		if err != nil {
+			return errBadDate
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(id)
+	if item == nil {
+		return errSongNotFound(id)
+	}
+	err := s.checkVersion(item)
+	if err != nil {
+		return err
+	}
+	if patch.Empty() {
+		return nil
+	}
+
+	name, group := item.name, item.group
+	if patch.Name != nil {
+		name = *patch.Name
+	}
+	if patch.Group != nil {
+		group = s.groupName(*patch.Group)
+	}
+	for _, stored := range s.songs {
+		if stored != item && stored.is(name, group) {
+			return errSongExists(name, group)
+		}
+	}
+
+	previous := item.state()
+	item.name, item.group = name, group
+	if patch.ReleaseDate != nil {
+		item.releaseDate = date
+	}
+	if patch.Text != nil {
+		item.text = *patch.Text
+		item.verses = song.ParseVerses(*patch.Text)
+	}
+	if patch.Link != nil {
+		item.link = *patch.Link
+	}
+	item.touch(previous)
+	s.record(id, song.RevisionUpdate, previous, item.state())
+
+	return nil
+}
+
+func (s *MemoryStorage) EnqueueEnrichment(songID int) (*song.Enrichment, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(songID) == nil {
+		return nil, errSongNotFound(songID)
+	}
+
+	return s.enqueue(songID), nil
+}
+
+// AddWithEnrichment adds song and its pending enrichment job under one lock
+func (s *MemoryStorage) AddWithEnrichment(name, group, releaseDate, text, link string) (int, *song.Enrichment, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := s.add(name, group, releaseDate, text, link)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return id, s.enqueue(id), nil
+}
+
+// enqueue creates pending job for song, it must be called with s.mu held
+func (s *MemoryStorage) enqueue(songID int) *song.Enrichment {
+
+	now := time.Now()
+	job := &song.Enrichment{
+		SongID:    songID,
+		Status:    song.EnrichmentPending,
+		NextRunAt: now,
+		UpdatedAt: now,
+	}
+	s.jobs[songID] = job
+
+	res := *job
+	return &res
+}
+
+func (s *MemoryStorage) GetEnrichment(songID int) (*song.Enrichment, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[songID]
+	if !ok {
+		return nil, errEnrichmentNotFound(songID)
+	}
+
+	res := *job
+	return &res, nil
+}
+
+func (s *MemoryStorage) ClaimEnrichment(staleAfter time.Duration) (*enrich.Job, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var claimed *song.Enrichment
+	for _, job := range s.jobs {
+		ready := job.Status == song.EnrichmentPending && !job.NextRunAt.After(now) ||
+			job.Status == song.EnrichmentRunning && !job.UpdatedAt.After(now.Add(-staleAfter))
+		if ready && (claimed == nil || job.NextRunAt.Before(claimed.NextRunAt)) {
+			claimed = job
+		}
+	}
+	if claimed == nil {
+		return nil, nil
+	}
+
+	claimed.Status = song.EnrichmentRunning
+	claimed.Attempts++
+	claimed.UpdatedAt = now
+
+	item := s.find(claimed.SongID)
+	return &enrich.Job{
+		SongID:   claimed.SongID,
+		Song:     item.name,
+		Group:    item.group,
+		Attempts: claimed.Attempts,
+	}, nil
+}
+
+func (s *MemoryStorage) CompleteEnrichment(songID int) error {
+
+	err := s.finishEnrichment(songID, song.EnrichmentDone, time.Now(), "")
+	if err != nil {
+		return err
+	}
+
+	return s.MarkEnriched(songID)
+}
+
+func (s *MemoryStorage) RetryEnrichment(songID int, nextRunAt time.Time, reason string) error {
+	return s.finishEnrichment(songID, song.EnrichmentPending, nextRunAt, reason)
+}
+
+func (s *MemoryStorage) FailEnrichment(songID int, reason string) error {
+	return s.finishEnrichment(songID, song.EnrichmentFailed, time.Now(), reason)
+}
+
+func (s *MemoryStorage) finishEnrichment(songID int, status string, nextRunAt time.Time, reason string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[songID]
+	if !ok || job.Status != song.EnrichmentRunning {
+		return nil
+	}
+
+	job.Status = status
+	job.NextRunAt = nextRunAt
+	job.LastError = reason
+	job.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (s *MemoryStorage) GetStaleSongs(enrichedBefore, incompleteBefore time.Time, limit int) ([]*song.Song, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stale := make([]*memorySong, 0)
+	for _, item := range s.songs {
+		if !item.deletedAt.IsZero() {
+			continue
+		}
+		incomplete := item.releaseDate.Start.IsZero() || item.text == "" || item.link == ""
+		if !item.lastEnrichedAt.Before(enrichedBefore) && !(incomplete && item.lastEnrichedAt.Before(incompleteBefore)) {
+			continue
+		}
+		if job, ok := s.jobs[item.id]; ok && (job.Status == song.EnrichmentPending || job.Status == song.EnrichmentRunning) {
+			continue
+		}
+		stale = append(stale, item)
+	}
+
+	//never enriched songs have zero time and go first, songs are already ordered by id
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].lastEnrichedAt.Before(stale[j].lastEnrichedAt)
+	})
+	if len(stale) > limit {
+		stale = stale[:limit]
+	}
+
+	songs := make([]*song.Song, 0, len(stale))
+	for _, item := range stale {
+		songs = append(songs, item.toSong())
+	}
+
+	return songs, nil
+}
+
+func (s *MemoryStorage) MarkEnriched(songID int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item := s.find(songID); item != nil {
+		item.lastEnrichedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (s *MemoryStorage) SaveRefreshRun(run *enrich.RefreshRun) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run.ID = len(s.runs) + 1
+	saved := *run
+	saved.Changes = make([]*enrich.RefreshChange, 0, len(run.Changes))
+	for _, change := range run.Changes {
+		res := *change
+		saved.Changes = append(saved.Changes, &res)
+	}
+	s.runs = append(s.runs, &saved)
+
+	return nil
+}
+
+func (s *MemoryStorage) GetRefreshRuns(limit, offset int) ([]*enrich.RefreshRun, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runs := make([]*enrich.RefreshRun, 0)
+	for i := len(s.runs) - 1 - offset; i >= 0; i-- {
+		if limit > 0 && len(runs) == limit {
+			break
+		}
+		//runs are not changed after saving, so they are shared with caller
+		runs = append(runs, s.runs[i])
+	}
+
+	return runs, nil
+}
+
+// ImportSongs holds the lock for the whole batch, so other requests see the batch saved at once
+func (s *MemoryStorage) ImportSongs(songs []*song.Song, onDuplicate string) ([]*song.ImportResult, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]*song.ImportResult, 0, len(songs))
+	for _, item := range songs {
+		result := &song.ImportResult{
+			Song:  item.Name,
+			Group: item.Group,
+		}
+		results = append(results, result)
+
+		var date song.ReleaseDate
+		if item.ReleaseDate != "" {
+			var err error
+			date, err = song.ParseReleaseDate(item.ReleaseDate)
+			if err != nil {
+				result.Status = song.ImportFailed
+				result.Error = errBadDate.Error()
+				continue
+			}
+		}
+
+		group := s.groupName(item.Group)
+		var existing *memorySong
+		for _, stored := range s.songs {
+			if stored.is(item.Name, group) {
+				existing = stored
+			}
+		}
+
+		switch {
+		case existing == nil:
+			stored := &memorySong{
+				id:          s.nextID,
+				name:        item.Name,
+				group:       group,
+				releaseDate: date,
+				text:        item.Text,
+				link:        item.Link,
+				verses:      song.ParseVerses(item.Text),
+				createdAt:   time.Now(),
+			}
+			stored.updatedAt = stored.createdAt
+			stored.version = 1
+			s.nextID++
+			s.songs = append(s.songs, stored)
+			s.record(stored.id, song.RevisionCreate, nil, stored.state())
+			result.ID = stored.id
+			result.Status = song.ImportCreated
+
+		case onDuplicate == song.DuplicateFail:
+			result.Status = song.ImportFailed
+			result.Error = errSongExists(item.Name, item.Group).Error()
+
+		case onDuplicate == song.DuplicateSkip || (date.Start.IsZero() && item.Text == "" && item.Link == ""):
+			result.ID = existing.id
+			result.Status = song.ImportSkipped
+
+		default:
+			previous := existing.state()
+			if !date.Start.IsZero() {
+				existing.releaseDate = date
+			}
+			if item.Text != "" {
+				existing.text = item.Text
+				existing.verses = song.ParseVerses(item.Text)
+			}
+			if item.Link != "" {
+				existing.link = item.Link
+			}
+			existing.touch(previous)
+			s.record(existing.id, song.RevisionUpdate, previous, existing.state())
+			result.ID = existing.id
+			result.Status = song.ImportUpdated
+		}
+	}
+
+	return results, nil
+}
+
+// find returns song which is not in trash, it must be called with s.mu held
+func (s *MemoryStorage) find(id int) *memorySong {
+	for _, item := range s.songs {
+		if item.id == id && item.deletedAt.IsZero() {
+			return item
+		}
+	}
+	return nil
+}
+
+// As returns storage which records revisions with actor, it shares songs with s
+func (s *MemoryStorage) As(actor string) song.Storage {
+	res := *s
+	res.actor = actor
+	return &res
+}
+
+// IfVersion returns storage which changes song only with given version, it shares songs with s
+func (s *MemoryStorage) IfVersion(version int) song.Storage {
+	res := *s
+	res.ifVersion = version
+	return &res
+}
+
+// checkVersion compares version of song with expected one of storage, it must be called with s.mu held
+func (s *MemoryStorage) checkVersion(item *memorySong) error {
+	if s.ifVersion != 0 && item.version != s.ifVersion {
+		return errVersionChanged(item.id, item.version)
+	}
+	return nil
+}
+
+// record saves revision of song, it must be called with s.mu held
+func (s *MemoryStorage) record(songID int, action string, previous, state *song.SongState) *song.Revision {
+
+	revision := song.NewRevision(len(s.revisions)+1, songID, action, revisionActor(s.actor), time.Now(), previous, state)
+	s.revisions = append(s.revisions, revision)
+
+	return revision
+}
+
+func (s *MemoryStorage) GetRevisions(songID, limit, offset int) ([]*song.Revision, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revisions := make([]*song.Revision, 0)
+	skipped := 0
+	for i := len(s.revisions) - 1; i >= 0; i-- {
+		if limit > 0 && len(revisions) == limit {
+			break
+		}
+		if s.revisions[i].SongID != songID {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		//revisions are not changed after saving, so they are shared with caller
+		revisions = append(revisions, s.revisions[i])
+	}
+
+	return revisions, nil
+}
+
+func (s *MemoryStorage) GetRevision(songID, revisionID int) (*song.Revision, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.findRevision(songID, revisionID)
+}
+
+// findRevision must be called with s.mu held
+func (s *MemoryStorage) findRevision(songID, revisionID int) (*song.Revision, error) {
+
+	if revisionID < 1 || revisionID > len(s.revisions) || s.revisions[revisionID-1].SongID != songID {
+		return nil, errRevisionNotFound(songID, revisionID)
+	}
+
+	return s.revisions[revisionID-1], nil
+}
+
+func (s *MemoryStorage) RestoreRevision(songID, revisionID int) (*song.Revision, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, err := s.findRevision(songID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if target.State == nil {
+		return nil, errRestoreDeleted(revisionID)
+	}
+
+	var date song.ReleaseDate
+	if target.State.ReleaseDate != "" {
+		date, err = song.ParseReleaseDate(target.State.ReleaseDate)
+		if err != nil {
+			return nil, errBadDate
+		}
+	}
+
+	item := s.find(songID)
+	if item == nil {
+		return nil, errSongNotFound(songID)
+	}
+	err = s.checkVersion(item)
+	if err != nil {
+		return nil, err
+	}
+
+	group := s.groupName(target.State.Group)
+	for _, stored := range s.songs {
+		if stored != item && stored.is(target.State.Name, group) {
+			return nil, errSongExists(target.State.Name, group)
+		}
+	}
+
+	previous := item.state()
+	item.name = target.State.Name
+	item.group = group
+	item.releaseDate = date
+	item.text = target.State.Text
+	item.verses = song.ParseVerses(target.State.Text)
+	item.link = target.State.Link
+	item.touch(previous)
+
+	return s.record(songID, song.RevisionRestore, previous, item.state()), nil
+}
+
+func (s *MemoryStorage) GetTrash(limit, offset int) ([]*song.TrashedSong, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	songs := make([]*song.TrashedSong, 0)
+	for _, item := range s.songs {
+		if !item.deletedAt.IsZero() {
+			songs = append(songs, &song.TrashedSong{Song: *item.toSong(), DeletedAt: item.deletedAt})
+		}
+	}
+
+	slices.SortStableFunc(songs, func(a, b *song.TrashedSong) int {
+		if c := b.DeletedAt.Compare(a.DeletedAt); c != 0 {
+			return c
+		}
+		return b.ID - a.ID
+	})
+
+	songs = songs[min(offset, len(songs)):]
+	if limit > 0 && len(songs) > limit {
+		songs = songs[:limit]
+	}
+
+	return songs, nil
+}
+
+func (s *MemoryStorage) RestoreTrashed(id int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.findTrashed(id)
+	if item == nil {
+		return errTrashedNotFound(id)
+	}
+
+	for _, stored := range s.songs {
+		if stored.is(item.name, item.group) {
+			return errSongExists(item.name, item.group)
+		}
+	}
+
+	item.deletedAt = time.Time{}
+	s.record(id, song.RevisionRestore, nil, item.state())
+
+	return nil
+}
+
+func (s *MemoryStorage) Purge(id int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findTrashed(id) == nil {
+		return errTrashedNotFound(id)
+	}
+
+	s.songs = slices.DeleteFunc(s.songs, func(item *memorySong) bool {
+		return item.id == id
+	})
+
+	return nil
+}
+
+func (s *MemoryStorage) PurgeTrash(deletedBefore time.Time) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := len(s.songs)
+	s.songs = slices.DeleteFunc(s.songs, func(item *memorySong) bool {
+		return !item.deletedAt.IsZero() && item.deletedAt.Before(deletedBefore)
+	})
+
+	return count - len(s.songs), nil
+}
+
+// findTrashed returns song which is in trash, it must be called with s.mu held
+func (s *MemoryStorage) findTrashed(id int) *memorySong {
+	for _, item := range s.songs {
+		if item.id == id && !item.deletedAt.IsZero() {
+			return item
+		}
+	}
+	return nil
+}
+
+func (item *memorySong) state() *song.SongState {
+	res := item.toSong()
+	return &song.SongState{
+		Name:        res.Name,
+		Group:       res.Group,
+		ReleaseDate: res.ReleaseDate,
+		Text:        res.Text,
+		Link:        res.Link,
+	}
+}
+
+func (item *memorySong) toSong() *song.Song {
+	res := &song.Song{
+		ID:        item.id,
+		Name:      item.name,
+		Group:     item.group,
+		Text:      item.text,
+		Link:      item.link,
+		CreatedAt: item.createdAt,
+		UpdatedAt: item.updatedAt,
+	}
+	res.SetVersion(item.version)
+	if !item.releaseDate.Start.IsZero() {
+		res.ReleaseDate = item.releaseDate.String()
+	}
+	return res
+}
+
+// is reports whether song is not deleted and has name and group, group is compared ignoring case
+func (item *memorySong) is(name, group string) bool {
+	return item.name == name && strings.EqualFold(item.group, group) && item.deletedAt.IsZero()
+}
+
+// groupName returns spelling of group stored with other songs, groups which differ only in case are the same group
+// like in postgres, where the first spelling is kept. It must be called with s.mu held
+func (s *MemoryStorage) groupName(group string) string {
+	for _, item := range s.songs {
+		if strings.EqualFold(item.group, group) {
+			return item.group
+		}
+	}
+	return group
+}
+
+// touch marks change of song when its state differs from previous, like trigger of songs in postgres
+func (item *memorySong) touch(previous *song.SongState) {
+	if *item.state() == *previous {
+		return
+	}
+	item.updatedAt = time.Now()
+	item.version++
+}
+
+func copyVerses(verses []*song.Verse) []*song.Verse {
+	res := make([]*song.Verse, 0, len(verses))
+	for _, verse := range verses {
+		item := *verse
+		res = append(res, &item)
+	}
+	return res
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage/storagetest"
+	"testing"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) song.Storage {
+		return NewMemoryStorage()
+	})
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// queryDialect is sql of storage used to compile song.Query to WHERE condition
+type queryDialect struct {
+	//columns of song, group and text predicates, they must not be NULL
+	columns map[string]string
+	//contains returns condition that column has value of placeholder as its part,
+	//escape prepares value for it
+	contains   func(column, placeholder string) string
+	escape     func(value string) string
+	releaseEnd string
+	//date converts bound of release period to query argument
+	date func(bound time.Time) interface{}
+}
+
+var pqQueryDialect = queryDialect{
+	columns: map[string]string{
+		song.QuerySong:  "song_name",
+		song.QueryGroup: "groups.name",
+		song.QueryText:  "coalesce(text, '')",
+	},
+	contains: func(column, placeholder string) string {
+		return fmt.Sprintf("%s LIKE CONCAT('%%',%s::text,'%%')", column, placeholder)
+	},
+	//% and _ of query are not wildcards, backslash is default escape character of LIKE
+	escape:     strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace,
+	releaseEnd: releaseDateEnd,
+	date: func(bound time.Time) interface{} {
+		return bound
+	},
+}
+
+var sqliteQueryDialect = queryDialect{
+	columns: map[string]string{
+		song.QuerySong:  "song_name",
+		song.QueryGroup: "group_name",
+		song.QueryText:  "coalesce(text, '')",
+	},
+	contains: func(column, placeholder string) string {
+		return fmt.Sprintf("instr(%s, %s) > 0", column, placeholder)
+	},
+	escape: func(value string) string {
+		return value
+	},
+	releaseEnd: sqliteReleaseDateEnd,
+	date: func(bound time.Time) interface{} {
+		return bound.Format(sqliteDateLayout)
+	},
+}
+
+// queryCondition compiles query to condition, values of query are passed only as arguments,
+// placeholder adds argument to query and returns its placeholder. Every predicate is true or false
+// and never NULL, so negation of predicate selects all other songs
+func queryCondition(query *song.Query, dialect queryDialect, placeholder func(arg interface{}) string) string {
+
+	switch query.Op {
+	case song.QueryAnd, song.QueryOr:
+		parts := make([]string, 0, len(query.Args))
+		for _, arg := range query.Args {
+			parts = append(parts, queryCondition(arg, dialect, placeholder))
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(query.Op)+" ") + ")"
+
+	case song.QueryNot:
+		return "NOT " + queryCondition(query.Args[0], dialect, placeholder)
+	}
+
+	switch query.Field {
+	case song.QueryAny:
+		return fmt.Sprintf("(%s OR %s)",
+			dialect.contains(dialect.columns[song.QuerySong], placeholder(dialect.escape(query.Value))),
+			dialect.contains(dialect.columns[song.QueryGroup], placeholder(dialect.escape(query.Value))),
+		)
+
+	case song.QueryLink:
+		if query.Value == "true" {
+			return "(link IS NOT NULL)"
+		}
+		return "(link IS NULL)"
+
+	case song.QueryReleased:
+		parts := []string{"release_date IS NOT NULL"}
+		if !query.From.IsZero() {
+			parts = append(parts, "release_date >= "+placeholder(dialect.date(query.From)))
+		}
+		if !query.Before.IsZero() {
+			parts = append(parts, dialect.releaseEnd+" <= "+placeholder(dialect.date(query.Before)))
+		}
+		return "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	return "(" + dialect.contains(dialect.columns[query.Field], placeholder(dialect.escape(query.Value))) + ")"
+}
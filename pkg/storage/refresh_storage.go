@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"SongLibrary/pkg/enrich"
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (s *Storage) GetStaleSongs(enrichedBefore, incompleteBefore time.Time, limit int) ([]*song.Song, error) {
+
+	rows, err := s.DB.Query(
+		`SELECT songs.id, song_name, groups.name, release_date, release_date_precision, text, link, songs.created_at, songs.updated_at, songs.version FROM songs JOIN groups ON groups.id = songs.group_id
+	WHERE (last_enriched_at IS NULL OR last_enriched_at < $1
+		OR (last_enriched_at < $2 AND (release_date IS NULL OR coalesce(text, '') = '' OR coalesce(link, '') = '')))
+	AND songs.deleted_at IS NULL
+	AND NOT EXISTS (
+		SELECT 1 FROM enrichment_jobs WHERE enrichment_jobs.song_id = songs.id AND enrichment_jobs.status IN ('pending', 'running')
+	)
+	ORDER BY last_enriched_at NULLS FIRST, songs.id
+	LIMIT $3`,
+		enrichedBefore, incompleteBefore, limit,
+	)
+	if err != nil {
+		log.Printf("method get stale songs query error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]*song.Song, 0)
+	for rows.Next() {
+		item, err := scanSong(rows)
+		if err != nil {
+			log.Printf("method get stale songs scan error: [%s]\n", err.Error())
+			return nil, err
+		}
+		songs = append(songs, item)
+	}
+
+	return songs, rows.Err()
+}
+
+func (s *Storage) MarkEnriched(songID int) error {
+
+	_, err := s.DB.Exec(`UPDATE songs SET last_enriched_at = now() WHERE id = $1`, songID)
+	if err != nil {
+		log.Printf("method mark enriched query error: [%s], song id: [%d]\n", err.Error(), songID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveRefreshRun(run *enrich.RefreshRun) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method save refresh run begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`INSERT INTO refresh_runs(started_at,finished_at,checked,updated,failed) VALUES($1,$2,$3,$4,$5) RETURNING id`,
+		run.StartedAt, run.FinishedAt, run.Checked, run.Updated, run.Failed,
+	).Scan(&run.ID)
+	if err != nil {
+		log.Printf("method save refresh run query error: [%s]\n", err.Error())
+		return err
+	}
+
+	for _, change := range run.Changes {
+		_, err = tx.Exec(
+			`INSERT INTO refresh_changes(run_id,song_id,field,old_value,new_value) VALUES($1,$2,$3,$4,$5)`,
+			run.ID, change.SongID, change.Field, change.OldValue, change.NewValue,
+		)
+		if err != nil {
+			log.Printf("method save refresh run change query error: [%s], run id: [%d]\n", err.Error(), run.ID)
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method save refresh run commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) GetRefreshRuns(limit, offset int) ([]*enrich.RefreshRun, error) {
+
+	query := "SELECT id, started_at, finished_at, checked, updated, failed FROM refresh_runs ORDER BY id DESC "
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get refresh runs query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+
+	runs, err := scanRefreshRuns(rows)
+	if err != nil {
+		log.Printf("method get refresh runs scan error: [%s]\n", err.Error())
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return runs, nil
+	}
+
+	ids := make([]int64, 0, len(runs))
+	for _, run := range runs {
+		ids = append(ids, int64(run.ID))
+	}
+
+	rows, err = s.DB.Query(
+		`SELECT run_id, song_id, field, old_value, new_value FROM refresh_changes WHERE run_id = ANY($1) ORDER BY id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		log.Printf("method get refresh runs changes query error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	err = scanRefreshChanges(rows, runs)
+	if err != nil {
+		log.Printf("method get refresh runs changes scan error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func scanRefreshRuns(rows *sql.Rows) ([]*enrich.RefreshRun, error) {
+
+	defer rows.Close()
+
+	runs := make([]*enrich.RefreshRun, 0)
+	for rows.Next() {
+		run := &enrich.RefreshRun{
+			Changes: make([]*enrich.RefreshChange, 0),
+		}
+		err := rows.Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.Checked, &run.Updated, &run.Failed)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// scanRefreshChanges reads rows with columns run_id, song_id, field, old_value, new_value to changes of runs
+func scanRefreshChanges(rows *sql.Rows, runs []*enrich.RefreshRun) error {
+
+	defer rows.Close()
+
+	byID := make(map[int]*enrich.RefreshRun, len(runs))
+	for _, run := range runs {
+		byID[run.ID] = run
+	}
+
+	for rows.Next() {
+		var runID int
+		change := &enrich.RefreshChange{}
+		err := rows.Scan(&runID, &change.SongID, &change.Field, &change.OldValue, &change.NewValue)
+		if err != nil {
+			return err
+		}
+		if run, ok := byID[runID]; ok {
+			run.Changes = append(run.Changes, change)
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// As returns storage which records revisions with actor, it shares connection pool with s
+func (s *Storage) As(actor string) song.Storage {
+	res := *s
+	res.actor = actor
+	return &res
+}
+
+// revisionActor returns actor of changes made by storage
+func revisionActor(actor string) string {
+	if actor == "" {
+		return song.ActorSystem
+	}
+	return actor
+}
+
+// marshalState returns JSON of state, nil state is NULL
+func marshalState(state *song.SongState) (sql.NullString, error) {
+
+	if state == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func unmarshalState(data sql.NullString) (*song.SongState, error) {
+
+	if !data.Valid {
+		return nil, nil
+	}
+
+	state := &song.SongState{}
+	err := json.Unmarshal([]byte(data.String), state)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// songState reads fields of song locked till the end of tx, nil state means there is no such song or it is in trash
+func songState(tx *sql.Tx, id int) (*song.SongState, error) {
+
+	var date sql.NullTime
+	var precision string
+	var text, link sql.NullString
+	state := &song.SongState{}
+
+	err := tx.QueryRow(
+		`SELECT song_name, groups.name, release_date, release_date_precision, text, link
+	FROM songs JOIN groups ON groups.id = songs.group_id
+	WHERE songs.id = $1 AND songs.deleted_at IS NULL
+	FOR UPDATE OF songs`, id,
+	).Scan(&state.Name, &state.Group, &date, &precision, &text, &link)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("song state query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+
+	if date.Valid {
+		state.ReleaseDate = song.NewReleaseDate(date.Time, precision).String()
+	}
+	state.Text = text.String
+	state.Link = link.String
+
+	return state, nil
+}
+
+// recordRevision saves revision of song changed in tx, previous is state before change,
+// state after change is read in tx, it is nil after delete
+func recordRevision(tx *sql.Tx, id int, action, actor string, previous *song.SongState) (*song.Revision, error) {
+
+	var state *song.SongState
+	if action != song.RevisionDelete {
+		var err error
+		state, err = songState(tx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	oldState, err := marshalState(previous)
+	if err != nil {
+		return nil, err
+	}
+	newState, err := marshalState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisionID int
+	var createdAt time.Time
+	actor = revisionActor(actor)
+	err = tx.QueryRow(
+		`INSERT INTO song_revisions(song_id,action,actor,old_state,new_state)
+	VALUES($1,$2,$3,$4,$5)
+	RETURNING id, created_at`,
+		id, action, actor, oldState, newState,
+	).Scan(&revisionID, &createdAt)
+	if err != nil {
+		log.Printf("record revision query error: [%s], id: [%d], action: [%s]\n", err.Error(), id, action)
+		return nil, err
+	}
+
+	return song.NewRevision(revisionID, id, action, actor, createdAt, previous, state), nil
+}
+
+func (s *Storage) GetRevisions(songID, limit, offset int) ([]*song.Revision, error) {
+
+	query := `SELECT id, song_id, action, actor, created_at, old_state, new_state FROM song_revisions
+	WHERE song_id = $1 ORDER BY id DESC `
+	placeholderNum := 2
+	args := []interface{}{songID}
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get revisions query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+
+	revisions, err := scanRevisions(rows)
+	if err != nil {
+		log.Printf("method get revisions scan error: [%s], id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (s *Storage) GetRevision(songID, revisionID int) (*song.Revision, error) {
+
+	rows, err := s.DB.Query(
+		`SELECT id, song_id, action, actor, created_at, old_state, new_state FROM song_revisions
+	WHERE song_id = $1 AND id = $2`,
+		songID, revisionID,
+	)
+	if err != nil {
+		log.Printf("method get revision query error: [%s], id: [%d], revision: [%d]\n", err.Error(), songID, revisionID)
+		return nil, err
+	}
+
+	revisions, err := scanRevisions(rows)
+	if err != nil {
+		log.Printf("method get revision scan error: [%s], id: [%d], revision: [%d]\n", err.Error(), songID, revisionID)
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, errRevisionNotFound(songID, revisionID)
+	}
+
+	return revisions[0], nil
+}
+
+func (s *Storage) RestoreRevision(songID, revisionID int) (*song.Revision, error) {
+
+	target, err := s.GetRevision(songID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if target.State == nil {
+		return nil, errRestoreDeleted(revisionID)
+	}
+
+	date, precision, err := parseReleaseDate(target.State.ReleaseDate)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method restore revision begin transaction error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	previous, err := songState(tx, songID)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return nil, errSongNotFound(songID)
+	}
+	err = checkVersion(tx, songID, s.ifVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID, err := upsertGroup(tx, target.State.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE songs SET song_name = $1, group_id = $2, release_date = $3, release_date_precision = $4,
+	text = NULLIF($5, ''), link = NULLIF($6, '')
+	WHERE id = $7`,
+		target.State.Name, groupID, date, precision, target.State.Text, target.State.Link, songID,
+	)
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("song [%s] of group [%s]", target.State.Name, target.State.Group))
+		if !isMapped(err) {
+			log.Printf("method restore revision query error: [%s], id: [%d], revision: [%d]\n", err.Error(), songID, revisionID)
+		}
+		return nil, err
+	}
+
+	err = replaceVerses(tx, songID, target.State.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := recordRevision(tx, songID, song.RevisionRestore, s.actor, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method restore revision commit error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// scanRevisions reads rows with columns id, song_id, action, actor, created_at, old_state, new_state,
+// created_at is timestamptz in postgres and text in sqlite
+func scanRevisions(rows *sql.Rows) ([]*song.Revision, error) {
+
+	defer rows.Close()
+
+	revisions := make([]*song.Revision, 0)
+	for rows.Next() {
+		var id, songID int
+		var action, actor string
+		var createdAt interface{}
+		var oldState, newState sql.NullString
+		err := rows.Scan(&id, &songID, &action, &actor, &createdAt, &oldState, &newState)
+		if err != nil {
+			return nil, err
+		}
+
+		var created time.Time
+		switch value := createdAt.(type) {
+		case time.Time:
+			created = value
+		case string:
+			created, err = time.Parse(sqliteTimeLayout, value)
+			if err != nil {
+				return nil, fmt.Errorf("parse creation time of revision [%d]: %w", id, err)
+			}
+		}
+
+		previous, err := unmarshalState(oldState)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal old state of revision [%d]: %w", id, err)
+		}
+		state, err := unmarshalState(newState)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal new state of revision [%d]: %w", id, err)
+		}
+
+		revisions = append(revisions, song.NewRevision(id, songID, action, actor, created, previous, state))
+	}
+
+	return revisions, rows.Err()
+}
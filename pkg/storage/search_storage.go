@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// searchLanguages must match text search configurations used in songs_search_vector_update trigger
+var searchLanguages = map[string]bool{
+	"simple":  true,
+	"english": true,
+	"russian": true,
+}
+
+func (s *Storage) Search(query, language string, limit, offset int) ([]*song.SearchResult, error) {
+
+	if strings.TrimSpace(query) == "" {
+		return nil, song.NewValidationError("q", "search query must be not empty")
+	}
+	if !searchLanguages[language] {
+		return nil, song.NewValidationError("lang", "language must be one of: simple, english, russian")
+	}
+
+	//the best matching verse is chosen for headline, so snippet shows where words were found
+	sqlQuery := `SELECT s.id, s.song_name, g.name, s.release_date, s.release_date_precision, s.link,
+	ts_rank(s.search_vector, q) AS rank, coalesce(v.headline, '')
+	FROM songs s
+	JOIN groups g ON g.id = s.group_id
+	CROSS JOIN websearch_to_tsquery($1::regconfig, $2) q
+	LEFT JOIN LATERAL (
+		SELECT ts_headline($1::regconfig, verses.text, q, 'HighlightAll=true') AS headline
+		FROM verses
+		WHERE verses.song_id = s.id
+		ORDER BY ts_rank(to_tsvector($1::regconfig, verses.text), q) DESC, verses.position
+		LIMIT 1
+	) v ON true
+	WHERE s.search_vector @@ q AND s.deleted_at IS NULL
+	ORDER BY rank DESC, s.id `
+	args := []interface{}{language, query}
+	placeholderNum := 3
+
+	if limit > 0 {
+		sqlQuery += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		sqlQuery += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(sqlQuery, args...)
+	if err != nil {
+		log.Printf("method search query error: [%s], args: [%v]\n", err.Error(), args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*song.SearchResult
+	for rows.Next() {
+		result := &song.SearchResult{}
+		var link sql.NullString
+		var date sql.NullTime
+		var precision string
+		err := rows.Scan(&result.ID, &result.Name, &result.Group, &date, &precision, &link, &result.Rank, &result.Headline)
+		if err != nil {
+			log.Printf("method search scan error: [%s], args: [%v]\n", err.Error(), args)
+			return nil, err
+		}
+
+		if date.Valid {
+			result.ReleaseDate = song.NewReleaseDate(date.Time, precision).String()
+		}
+		result.Link = link.String
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
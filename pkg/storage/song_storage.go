@@ -3,14 +3,34 @@ package storage
 import (
 	"SongLibrary/pkg/song"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type Storage struct {
 	DB *sql.DB
+	//actor is recorded in revisions, empty means song.ActorSystem
+	actor string
+	//ifVersion is version song must have to be changed, zero means any version
+	ifVersion int
+}
+
+// countEstimateThreshold is number of rows expected by planner from which songs are not counted exactly
+const countEstimateThreshold = 10000
+
+var sortColumns = map[string]sortColumn{
+	song.SortName:        {expr: "song_name"},
+	song.SortGroup:       {expr: "groups.name"},
+	song.SortReleaseDate: {expr: "release_date", nullable: true},
+	song.SortID:          {expr: "songs.id"},
+	song.SortCreatedAt:   {expr: "songs.created_at"},
+	song.SortUpdatedAt:   {expr: "songs.updated_at"},
 }
 
 func NewStorage(db *sql.DB) *Storage {
@@ -20,82 +40,47 @@ func NewStorage(db *sql.DB) *Storage {
 
 }
 
-func (s *Storage) GetAll(limit, offset int, songNameFragment, groupNameFragment, year, textFragment, linkExist string) ([]*song.Song, error) {
+func (s *Storage) GetAll(filter song.Filter) ([]*song.Song, error) {
 
 	var songs []*song.Song
-	query := "SELECT * FROM songs "
-	placeholderNum := 1
-	args := make([]interface{}, 0)
+	query := "SELECT songs.id, song_name, groups.name, release_date, release_date_precision, text, link, songs.created_at, songs.updated_at, songs.version FROM songs JOIN groups ON groups.id = songs.group_id "
 
-	if songNameFragment != "" {
-		if placeholderNum == 1 {
-			query += "WHERE "
-		}
-		query += fmt.Sprintf("song_name LIKE CONCAT('%%',$%d::text,'%%') AND ", placeholderNum)
-		placeholderNum++
-		args = append(args, songNameFragment)
-	}
-
-	if groupNameFragment != "" {
-		if placeholderNum == 1 {
-			query += "WHERE "
-		}
-		query += fmt.Sprintf("group_name LIKE CONCAT('%%',$%d::text,'%%') AND ", placeholderNum)
-		placeholderNum++
-		args = append(args, groupNameFragment)
+	conditions, args, err := filterConditions(filter, 1)
+	if err != nil {
+		return nil, err
 	}
+	placeholderNum := len(args) + 1
 
-	if year != "" {
-		if placeholderNum == 1 {
-			query += "WHERE "
-		}
-
-		startDate, err := time.Parse("2006", year)
+	//backward page is read in reverse order and reversed after reading
+	keys := filter.SortKeys()
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if filter.Cursor != nil {
+		values, err := cursorValues(filter.Cursor, keys)
 		if err != nil {
-			return nil, s.GetErrorBadDate()
-		}
-		endDate, _ := time.Parse("02.01.2006", "31.12."+year)
-
-		query += fmt.Sprintf("release_date >= $%d AND release_date <= $%d AND ", placeholderNum, placeholderNum+1)
-		placeholderNum += 2
-		args = append(args, startDate, endDate)
-	}
-
-	if textFragment != "" {
-		if placeholderNum == 1 {
-			query += "WHERE "
+			return nil, err
 		}
-		query += fmt.Sprintf("text LIKE CONCAT('%%',$%d::text,'%%') AND ", placeholderNum)
-		placeholderNum++
-		args = append(args, textFragment)
+		conditions = append(conditions, keysetCondition(keys, sortColumns, values, backward, func(arg interface{}) string {
+			args = append(args, arg)
+			placeholderNum++
+			return fmt.Sprintf("$%d", placeholderNum-1)
+		}))
 	}
 
-	if strings.ToLower(linkExist) == "true" {
-		if placeholderNum == 1 {
-			query += "WHERE "
-		}
-		query += "link is not null AND "
-
-	} else if strings.ToLower(linkExist) == "false" {
-		if placeholderNum == 1 {
-			query += "WHERE "
-		}
-		query += "link is null AND "
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + " "
 	}
+	query += "ORDER BY " + orderBy(keys, sortColumns, backward) + " "
 
-	query = strings.TrimSuffix(query, "AND ")
-	query += "ORDER BY id "
-
-	if limit > 0 {
+	if filter.Limit > 0 {
 		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
 		placeholderNum++
-		args = append(args, limit)
+		args = append(args, filter.Limit)
 	}
 
-	if offset > 0 {
+	if filter.Offset > 0 {
 		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
 		placeholderNum++
-		args = append(args, offset)
+		args = append(args, filter.Offset)
 	}
 
 	rows, err := s.DB.Query(query, args...)
@@ -105,95 +90,348 @@ func (s *Storage) GetAll(limit, offset int, songNameFragment, groupNameFragment,
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var songName, groupName, releaseDate string
-		var text, link sql.NullString
-		var date sql.NullTime
-		var id int
-		err := rows.Scan(&id, &songName, &groupName, &date, &text, &link)
+		item, err := scanSong(rows)
 		if err != nil {
 			log.Printf("method get all scan error: [%s], query: [%s]\n", err.Error(), query)
 			return nil, err
 		}
 
-		if date.Valid {
-			releaseDate = date.Time.Format("02.01.2006")
-		} else {
-			releaseDate = ""
+		songs = append(songs, item)
+	}
+
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	return songs, rows.Err()
+}
+
+func (s *Storage) GetSong(id int) (*song.Song, error) {
+
+	rows, err := s.DB.Query(
+		`SELECT songs.id, song_name, groups.name, release_date, release_date_precision, text, link, songs.created_at, songs.updated_at, songs.version
+	FROM songs JOIN groups ON groups.id = songs.group_id
+	WHERE songs.id = $1 AND songs.deleted_at IS NULL`, id,
+	)
+	if err != nil {
+		log.Printf("method get song query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
 		}
+		return nil, errSongNotFound(id)
+	}
+
+	item, err := scanSong(rows)
+	if err != nil {
+		log.Printf("method get song scan error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+
+	return item, nil
+}
 
-		songs = append(songs, &song.Song{
-			ID:          id,
-			Name:        songName,
-			Group:       groupName,
-			ReleaseDate: releaseDate,
-			Text:        text.String,
-			Link:        link.String,
-		})
+// CountSongs uses planner estimate instead of count(*) when planner expects many rows
+func (s *Storage) CountSongs(filter song.Filter) (int, bool, error) {
+
+	query := "FROM songs JOIN groups ON groups.id = songs.group_id "
+	conditions, args, err := filterConditions(filter, 1)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var plan []byte
+	err = s.DB.QueryRow("EXPLAIN (FORMAT JSON) SELECT 1 "+query, args...).Scan(&plan)
+	if err != nil {
+		log.Printf("method count songs explain query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return 0, false, err
+	}
+
+	explain := []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}{}
+	err = json.Unmarshal(plan, &explain)
+	if err == nil && len(explain) > 0 && explain[0].Plan.Rows > countEstimateThreshold {
+		return int(explain[0].Plan.Rows), true, nil
 	}
 
-	return songs, nil
+	var count int
+	err = s.DB.QueryRow("SELECT count(*) "+query, args...).Scan(&count)
+	if err != nil {
+		log.Printf("method count songs query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return 0, false, err
+	}
+
+	return count, false, nil
 }
 
-func (s *Storage) Get(id int) (string, error) {
+// releaseDateEnd is the day after the last day of release date period
+const releaseDateEnd = "(release_date + CASE release_date_precision WHEN 'year' THEN interval '1 year' WHEN 'month' THEN interval '1 month' ELSE interval '1 day' END)"
+
+// releaseBounds joins release year of filter with its release period, songs must be released
+// within [from, before), zero bound is not used
+func releaseBounds(filter song.Filter) (from time.Time, before time.Time, err error) {
 
-	var text sql.NullString
-	err := s.DB.QueryRow(
-		`SELECT text FROM songs WHERE id = $1`, id,
-	).Scan(&text)
+	from, before = filter.ReleasedFrom, filter.ReleasedBefore
+	if filter.Year == "" {
+		return from, before, nil
+	}
 
+	year, err := time.Parse("2006", filter.Year)
 	if err != nil {
-		if err.Error() != s.GetErrorNoRows().Error() {
-			log.Printf("method get query error: [%s], id: [%d]\n", err.Error(), id)
+		return time.Time{}, time.Time{}, errBadYear
+	}
+
+	if year.After(from) {
+		from = year
+	}
+	if before.IsZero() || year.AddDate(1, 0, 0).Before(before) {
+		before = year.AddDate(1, 0, 0)
+	}
+
+	return from, before, nil
+}
+
+// parseReleaseDate returns NULL date for empty value, precision of NULL date is day
+func parseReleaseDate(value string) (sql.NullTime, string, error) {
+
+	if value == "" {
+		return sql.NullTime{}, song.PrecisionDay, nil
+	}
+
+	date, err := song.ParseReleaseDate(value)
+	if err != nil {
+		return sql.NullTime{}, "", errBadDate
+	}
+
+	return sql.NullTime{Time: date.Start, Valid: true}, date.Precision, nil
+}
+
+// filterConditions returns WHERE conditions for filter, numbering of placeholders starts from placeholderNum
+func filterConditions(filter song.Filter, placeholderNum int) ([]string, []interface{}, error) {
+
+	//songs in trash are never listed
+	conditions := []string{"songs.deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+
+	if filter.SongName != "" {
+		conditions = append(conditions, fmt.Sprintf("song_name LIKE CONCAT('%%',$%d::text,'%%')", placeholderNum))
+		placeholderNum++
+		args = append(args, filter.SongName)
+	}
+
+	if filter.Group != "" {
+		conditions = append(conditions, fmt.Sprintf("groups.name LIKE CONCAT('%%',$%d::text,'%%')", placeholderNum))
+		placeholderNum++
+		args = append(args, filter.Group)
+	}
+
+	from, before, err := releaseBounds(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !from.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("release_date >= $%d", placeholderNum))
+		placeholderNum++
+		args = append(args, from)
+	}
+
+	if !before.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", releaseDateEnd, placeholderNum))
+		placeholderNum++
+		args = append(args, before)
+	}
+
+	if filter.Text != "" {
+		conditions = append(conditions, fmt.Sprintf("text LIKE CONCAT('%%',$%d::text,'%%')", placeholderNum))
+		placeholderNum++
+		args = append(args, filter.Text)
+	}
+
+	if strings.ToLower(filter.Link) == "true" {
+		conditions = append(conditions, "link is not null")
+	} else if strings.ToLower(filter.Link) == "false" {
+		conditions = append(conditions, "link is null")
+	}
+
+	if filter.Query != nil {
+		conditions = append(conditions, queryCondition(filter.Query, pqQueryDialect, func(arg interface{}) string {
+			args = append(args, arg)
+			placeholderNum++
+			return fmt.Sprintf("$%d", placeholderNum-1)
+		}))
+	}
+
+	if filter.AlbumID != 0 {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM album_songs WHERE album_songs.song_id = songs.id AND album_songs.album_id = $%d)", placeholderNum))
+		placeholderNum++
+		args = append(args, filter.AlbumID)
+	}
+
+	if len(filter.Tags) > 0 {
+		names := uniqueTagNames(filter.Tags)
+		matched := fmt.Sprintf("SELECT count(*) FROM song_tags JOIN tags ON tags.id = song_tags.tag_id WHERE song_tags.song_id = songs.id AND lower(tags.name) = ANY($%d)", placeholderNum)
+		placeholderNum++
+		args = append(args, pq.Array(names))
+		if filter.AnyTag {
+			conditions = append(conditions, fmt.Sprintf("(%s) > 0", matched))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s) = %d", matched, len(names)))
 		}
-		return "", err
 	}
 
-	return text.String, nil
+	return conditions, args, nil
+}
+
+// scanSong reads row with columns id, song name, group name, release_date, release_date_precision, text, link,
+// created_at, updated_at, version
+func scanSong(rows *sql.Rows, extra ...interface{}) (*song.Song, error) {
+
+	var text, link sql.NullString
+	var date sql.NullTime
+	var precision string
+	var version int
+	item := &song.Song{}
+
+	err := rows.Scan(append([]interface{}{&item.ID, &item.Name, &item.Group, &date, &precision, &text, &link, &item.CreatedAt, &item.UpdatedAt, &version}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	item.SetVersion(version)
+
+	if date.Valid {
+		item.ReleaseDate = song.NewReleaseDate(date.Time, precision).String()
+	}
+	item.Text = text.String
+	item.Link = link.String
+
+	return item, nil
 }
 
 func (s *Storage) Add(name, group, releaseDate, text, link string) (int, error) {
 
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method add begin transaction error: [%s]\n", err.Error())
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	insertID, err := s.addSong(tx, name, group, releaseDate, text, link)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method add commit error: [%s]\n", err.Error())
+		return 0, err
+	}
+
+	return insertID, nil
+}
+
+// addSong inserts song with its verses and create revision in tx
+func (s *Storage) addSong(tx *sql.Tx, name, group, releaseDate, text, link string) (int, error) {
+
+	//empty release date is stored as NULL, it is filled later by enrichment
 	var insertID int
-	date, err := time.Parse("02.01.2006", releaseDate)
+	date, precision, err := parseReleaseDate(releaseDate)
+	if err != nil {
+		return 0, err
+	}
+
+	groupID, err := upsertGroup(tx, group)
 	if err != nil {
 		return 0, err
 	}
 
-	err = s.DB.QueryRow(
+	err = tx.QueryRow(
 		`INSERT INTO 
-	songs(song_name,group_name,release_date,text,link) 
-	VALUES($1,$2,$3,$4,$5) 
+	songs(song_name,group_id,release_date,release_date_precision,text,link) 
+	VALUES($1,$2,$3,$4,NULLIF($5,''),NULLIF($6,'')) 
 	RETURNING id`,
-		name, group, date, text, link,
+		name, groupID, date, precision, text, link,
 	).Scan(&insertID)
 
 	if err != nil {
-		if err.Error() != s.GetErrorAlreadyExist().Error() {
+		err = pqError(err, fmt.Sprintf("song [%s] of group [%s]", name, group))
+		if !isMapped(err) {
 			log.Printf("method add query error: [%s], args: [song: %s, group: %s, releaseDate: %s, link: %s]\n", err.Error(), name, group, releaseDate, link)
 		}
 		return 0, err
 	}
 
+	err = replaceVerses(tx, insertID, text)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = recordRevision(tx, insertID, song.RevisionCreate, s.actor, nil)
+	if err != nil {
+		return 0, err
+	}
+
 	return insertID, nil
 }
 
 func (s *Storage) Delete(id int) error {
 
-	result, err := s.DB.Exec(
-		`DELETE FROM songs WHERE id = $1`, id,
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method delete begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := songState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = checkVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+
+	//song is moved to trash, its pending enrichment is dropped
+	_, err = tx.Exec(
+		`UPDATE songs SET deleted_at = now() WHERE id = $1`, id,
 	)
 	if err != nil {
 		log.Printf("method delete query error: [%s], id: [%d]\n", err.Error(), id)
 		return err
 	}
 
-	num, err := result.RowsAffected()
+	_, err = tx.Exec(
+		`DELETE FROM enrichment_jobs WHERE song_id = $1`, id,
+	)
 	if err != nil {
+		log.Printf("method delete enrichment job query error: [%s], id: [%d]\n", err.Error(), id)
 		return err
 	}
 
-	if num == 0 {
-		return s.GetErrorBadID()
+	_, err = recordRevision(tx, id, song.RevisionDelete, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method delete commit error: [%s]\n", err.Error())
+		return err
 	}
 
 	return nil
@@ -206,13 +444,13 @@ func (s *Storage) Update(id int, releaseDate, text, link string) error {
 	args := make([]interface{}, 0)
 
 	if releaseDate != "" {
-		date, err := time.Parse("02.01.2006", releaseDate)
+		date, precision, err := parseReleaseDate(releaseDate)
 		if err != nil {
-			return s.GetErrorBadDate()
+			return err
 		}
-		query += fmt.Sprintf("release_date = $%d, ", placeholderNum)
-		placeholderNum++
-		args = append(args, date)
+		query += fmt.Sprintf("release_date = $%d, release_date_precision = $%d, ", placeholderNum, placeholderNum+1)
+		placeholderNum += 2
+		args = append(args, date, precision)
 	}
 
 	if text != "" {
@@ -228,7 +466,7 @@ func (s *Storage) Update(id int, releaseDate, text, link string) error {
 	}
 
 	if placeholderNum == 1 {
-		return s.GetErrorNoUpdate()
+		return errNoUpdate
 	}
 
 	query = strings.TrimSuffix(query, ", ")
@@ -236,9 +474,31 @@ func (s *Storage) Update(id int, releaseDate, text, link string) error {
 	query += fmt.Sprintf(" WHERE id = $%d", placeholderNum)
 	args = append(args, id)
 
-	result, err := s.DB.Exec(query, args...)
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method update begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := songState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = checkVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
-		log.Printf("method update query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		err = pqError(err, fmt.Sprintf("song with id [%d]", id))
+		if !isMapped(err) {
+			log.Printf("method update query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		}
 		return err
 	}
 
@@ -248,28 +508,141 @@ func (s *Storage) Update(id int, releaseDate, text, link string) error {
 	}
 
 	if num == 0 {
-		return s.GetErrorBadID()
+		return errSongNotFound(id)
+	}
+
+	if text != "" {
+		err = replaceVerses(tx, id, text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = recordRevision(tx, id, song.RevisionUpdate, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method update commit error: [%s]\n", err.Error())
+		return err
 	}
 
 	return nil
 }
 
-func (s *Storage) GetErrorAlreadyExist() error {
-	return fmt.Errorf(`pq: duplicate key value violates unique constraint "songs_song_name_group_name_key"`)
-}
+// Patch applies merge patch in one transaction, song and group keep unique pair like in Add
+func (s *Storage) Patch(id int, patch *song.SongPatch) error {
 
-func (s *Storage) GetErrorNoUpdate() error {
-	return fmt.Errorf("no data to update")
-}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method patch begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
 
-func (s *Storage) GetErrorBadID() error {
-	return fmt.Errorf("bad id")
-}
+	previous, err := songState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = checkVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+	if patch.Empty() {
+		return nil
+	}
+
+	query := "UPDATE songs SET "
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+
+	if patch.Name != nil {
+		query += fmt.Sprintf("song_name = $%d, ", placeholderNum)
+		placeholderNum++
+		args = append(args, *patch.Name)
+	}
+
+	if patch.Group != nil {
+		groupID, err := upsertGroup(tx, *patch.Group)
+		if err != nil {
+			return err
+		}
+		query += fmt.Sprintf("group_id = $%d, ", placeholderNum)
+		placeholderNum++
+		args = append(args, groupID)
+	}
+
+	if patch.ReleaseDate != nil {
+		date, precision, err := parseReleaseDate(*patch.ReleaseDate)
+		if err != nil {
+			return err
+		}
+		query += fmt.Sprintf("release_date = $%d, release_date_precision = $%d, ", placeholderNum, placeholderNum+1)
+		placeholderNum += 2
+		args = append(args, date, precision)
+	}
 
-func (s *Storage) GetErrorBadDate() error {
-	return fmt.Errorf("bad date format")
+	if patch.Text != nil {
+		query += fmt.Sprintf("text = NULLIF($%d, ''), ", placeholderNum)
+		placeholderNum++
+		args = append(args, *patch.Text)
+	}
+
+	if patch.Link != nil {
+		query += fmt.Sprintf("link = NULLIF($%d, ''), ", placeholderNum)
+		placeholderNum++
+		args = append(args, *patch.Link)
+	}
+
+	query = strings.TrimSuffix(query, ", ")
+	query += fmt.Sprintf(" WHERE id = $%d", placeholderNum)
+	args = append(args, id)
+
+	_, err = tx.Exec(query, args...)
+	if err != nil {
+		err = pqError(err, patchSubject(previous, patch))
+		if !isMapped(err) {
+			log.Printf("method patch query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		}
+		return err
+	}
+
+	if patch.Text != nil {
+		err = replaceVerses(tx, id, *patch.Text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = recordRevision(tx, id, song.RevisionUpdate, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method patch commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
 }
 
-func (s *Storage) GetErrorNoRows() error {
-	return fmt.Errorf("sql: no rows in result set")
+// patchSubject describes song after patch for error messages
+func patchSubject(previous *song.SongState, patch *song.SongPatch) string {
+
+	name, group := previous.Name, previous.Group
+	if patch.Name != nil {
+		name = *patch.Name
+	}
+	if patch.Group != nil {
+		group = *patch.Group
+	}
+
+	return fmt.Sprintf("song [%s] of group [%s]", name, group)
 }
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"SongLibrary/pkg/album"
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage/storagetest"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+// postgresDSNEnv names variable with DSN of test database, all its tables are truncated by tests
+const postgresDSNEnv = "SONGS_TEST_POSTGRES_DSN"
+
+// openTestPostgres migrates database of SONGS_TEST_POSTGRES_DSN, test is skipped when it is not set
+func openTestPostgres(t *testing.T) *sql.DB {
+
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	migration, err := migrate.New("file://../../migration", dsn)
+	if err != nil {
+		t.Fatalf("create migration error: %v", err)
+	}
+	err = migration.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("migration up error: %v", err)
+	}
+	migration.Close()
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open postgres error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func newTestPostgres(t *testing.T, db *sql.DB) *Storage {
+
+	_, err := db.Exec(`TRUNCATE songs, groups, albums, album_songs, tags, song_tags, verses,
+	enrichment_jobs, refresh_runs, refresh_changes, song_revisions RESTART IDENTITY CASCADE`)
+	if err != nil {
+		t.Fatalf("truncate error: %v", err)
+	}
+	return NewStorage(db)
+}
+
+func TestPostgresStorage(t *testing.T) {
+
+	db := openTestPostgres(t)
+	storagetest.Run(t, func(t *testing.T) song.Storage {
+		return newTestPostgres(t, db)
+	})
+}
+
+// TestPostgresDeleteGroup checks that songs in trash do not keep group and are purged only when group is deleted
+func TestPostgresDeleteGroup(t *testing.T) {
+
+	s := newTestPostgres(t, openTestPostgres(t))
+
+	trashed, err := s.Add("uprising", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	live, err := s.Add("starlight", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	var groupID int
+	err = s.DB.QueryRow(`SELECT group_id FROM songs WHERE id = $1`, trashed).Scan(&groupID)
+	if err != nil {
+		t.Fatalf("get group id error: %v", err)
+	}
+	err = s.Delete(trashed)
+	if err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+
+	//live song keeps group, trashed song is not purged
+	err = s.DeleteGroup(groupID)
+	if !errors.Is(err, song.ErrConflict) {
+		t.Fatalf("delete group with song: got %v, want ErrConflict", err)
+	}
+	items, err := s.GetTrash(0, 0)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("trash after failed delete of group: got %d songs and error %v, want 1 song", len(items), err)
+	}
+
+	err = s.Delete(live)
+	if err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	err = s.DeleteGroup(groupID)
+	if err != nil {
+		t.Fatalf("delete group with songs in trash error: %v", err)
+	}
+	items, err = s.GetTrash(0, 0)
+	if err != nil || len(items) != 0 {
+		t.Errorf("trash after delete of group: got %d songs and error %v, want empty", len(items), err)
+	}
+	_, err = s.GetGroup(groupID)
+	if !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("get deleted group: got %v, want ErrNotFound", err)
+	}
+}
+
+// TestPostgresRenameGroupVersion checks that songs of renamed group get new version, their group in answers is changed
+func TestPostgresRenameGroupVersion(t *testing.T) {
+
+	s := newTestPostgres(t, openTestPostgres(t))
+
+	id, err := s.Add("uprising", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	item, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	var groupID int
+	err = s.DB.QueryRow(`SELECT group_id FROM songs WHERE id = $1`, id).Scan(&groupID)
+	if err != nil {
+		t.Fatalf("get group id error: %v", err)
+	}
+
+	err = s.RenameGroup(groupID, "Muse (UK)")
+	if err != nil {
+		t.Fatalf("rename group error: %v", err)
+	}
+	renamed, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if renamed.Group != "Muse (UK)" || renamed.Version != item.Version+1 || renamed.ETag == item.ETag {
+		t.Errorf("after rename of group: got group %s, version %d, ETag %s, want Muse (UK), %d and new ETag",
+			renamed.Group, renamed.Version, renamed.ETag, item.Version+1)
+	}
+}
+
+func TestPostgresAlbum(t *testing.T) {
+
+	s := newTestPostgres(t, openTestPostgres(t))
+
+	id, err := s.AddAlbum(&album.Album{Title: "the resistance", Group: "muse", ReleaseDate: "09.2009"})
+	if err != nil {
+		t.Fatalf("add album error: %v", err)
+	}
+	item, err := s.GetAlbum(id)
+	if err != nil {
+		t.Fatalf("get album error: %v", err)
+	}
+	if item.ReleaseDate != "09.2009" {
+		t.Errorf("release date is %s, want 09.2009", item.ReleaseDate)
+	}
+
+	_, err = s.AddAlbum(&album.Album{Title: "absolution", Group: "muse", ReleaseDate: "2003-09-15"})
+	if !errors.Is(err, song.ErrValidation) {
+		t.Fatalf("add album with bad date: got %v, want ErrValidation", err)
+	}
+
+	//songs in trash are not attached
+	songID, err := s.Add("uprising", "muse", "", "", "")
+	if err != nil {
+		t.Fatalf("add error: %v", err)
+	}
+	err = s.Delete(songID)
+	if err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	err = s.AttachSong(id, &album.Track{SongID: songID})
+	if !errors.Is(err, song.ErrNotFound) {
+		t.Fatalf("attach song in trash: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errBadCursor = song.NewValidationError("cursor", "bad cursor")
+
+// sortColumn is column of songs list order, NULLs of nullable column are placed after other values
+type sortColumn struct {
+	expr     string
+	nullable bool
+}
+
+// orderBy returns ORDER BY expression for keys, backward page is read in reverse order
+func orderBy(keys []song.SortKey, columns map[string]sortColumn, backward bool) string {
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		column := columns[key.Field]
+		part := column.expr
+		if key.Desc != backward {
+			part += " DESC"
+		}
+		if column.nullable && backward {
+			part += " NULLS FIRST"
+		} else if column.nullable {
+			part += " NULLS LAST"
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition returns condition selecting rows after cursor in order of keys, values are cursor values
+// converted to column types with nil for NULL, placeholder adds argument to query and returns its placeholder.
+// For backward page rows before cursor are selected
+func keysetCondition(keys []song.SortKey, columns map[string]sortColumn, values []interface{}, backward bool, placeholder func(arg interface{}) string) string {
+
+	//(k1 after v1) OR (k1 = v1 AND k2 after v2) OR ...
+	alternatives := make([]string, 0, len(keys))
+	equal := make([]string, 0, len(keys))
+	for i, key := range keys {
+		column := columns[key.Field]
+		value := values[i]
+
+		var after string
+		switch {
+		case value == nil && backward:
+			after = column.expr + " IS NOT NULL"
+		case value == nil:
+			//nothing is placed after NULLs
+		default:
+			op := ">"
+			if key.Desc != backward {
+				op = "<"
+			}
+			after = fmt.Sprintf("%s %s %s", column.expr, op, placeholder(value))
+			if column.nullable && !backward {
+				after = fmt.Sprintf("(%s OR %s IS NULL)", after, column.expr)
+			}
+		}
+		if after != "" {
+			alternatives = append(alternatives, "("+strings.Join(append(slices.Clone(equal), after), " AND ")+")")
+		}
+
+		if i == len(keys)-1 {
+			break
+		}
+		if value == nil {
+			equal = append(equal, column.expr+" IS NULL")
+		} else {
+			equal = append(equal, fmt.Sprintf("%s = %s", column.expr, placeholder(value)))
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return "1 = 0"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// cursorValues converts values of cursor to release date and times as time.Time, id as int and names as strings
+func cursorValues(cursor *song.Cursor, keys []song.SortKey) ([]interface{}, error) {
+
+	values := make([]interface{}, 0, len(keys))
+	for i, key := range keys {
+		if cursor.Values[i] == nil {
+			if key.Field != song.SortReleaseDate {
+				return nil, errBadCursor
+			}
+			values = append(values, nil)
+			continue
+		}
+
+		value := *cursor.Values[i]
+		var converted interface{}
+		var err error
+		switch key.Field {
+		case song.SortReleaseDate:
+			var date song.ReleaseDate
+			date, err = song.ParseReleaseDate(value)
+			converted = date.Start
+		case song.SortID:
+			converted, err = strconv.Atoi(value)
+		case song.SortCreatedAt, song.SortUpdatedAt:
+			converted, err = time.Parse(time.RFC3339Nano, value)
+		default:
+			converted = value
+		}
+		if err != nil {
+			return nil, errBadCursor
+		}
+		values = append(values, converted)
+	}
+
+	return values, nil
+}
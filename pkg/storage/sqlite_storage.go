@@ -0,0 +1,1563 @@
+package storage
+
+import (
+	"SongLibrary/pkg/enrich"
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dates and times are stored as ISO 8601 text, so they can be compared as strings, times are in UTC
+const (
+	sqliteDateLayout = "2006-01-02"
+	sqliteTimeLayout = "2006-01-02 15:04:05.000"
+)
+
+var sqliteSortColumns = map[string]sortColumn{
+	song.SortName:        {expr: "song_name"},
+	song.SortGroup:       {expr: "group_name"},
+	song.SortReleaseDate: {expr: "release_date", nullable: true},
+	song.SortID:          {expr: "id"},
+	song.SortCreatedAt:   {expr: "created_at"},
+	song.SortUpdatedAt:   {expr: "updated_at"},
+}
+
+type SQLiteStorage struct {
+	DB *sql.DB
+	//actor is recorded in revisions, empty means song.ActorSystem
+	actor string
+	//ifVersion is version song must have to be changed, zero means any version
+	ifVersion int
+}
+
+func NewSQLiteStorage(db *sql.DB) *SQLiteStorage {
+	return &SQLiteStorage{
+		DB: db,
+	}
+}
+
+func (s *SQLiteStorage) GetAll(filter song.Filter) ([]*song.Song, error) {
+
+	conditions, args, err := sqliteFilterConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var songs []*song.Song
+	query := "SELECT id, song_name, group_name, release_date, release_date_precision, text, link, created_at, updated_at, version FROM songs "
+
+	//backward page is read in reverse order and reversed after reading
+	keys := filter.SortKeys()
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if filter.Cursor != nil {
+		values, err := cursorValues(filter.Cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			if parsed, ok := value.(time.Time); ok && keys[i].Field == song.SortReleaseDate {
+				values[i] = parsed.Format(sqliteDateLayout)
+			} else if ok {
+				values[i] = parsed.UTC().Format(sqliteTimeLayout)
+			}
+		}
+		//placeholders of condition are used several times, so they are numbered, next "?" takes the next number
+		conditions = append(conditions, keysetCondition(keys, sqliteSortColumns, values, backward, func(arg interface{}) string {
+			args = append(args, arg)
+			return fmt.Sprintf("?%d", len(args))
+		}))
+	}
+
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + " "
+	}
+	query += "ORDER BY " + orderBy(keys, sqliteSortColumns, backward) + " "
+
+	//sqlite does not accept OFFSET without LIMIT, -1 means no filter.Limit
+	if filter.Limit > 0 || filter.Offset > 0 {
+		if filter.Limit == 0 {
+			filter.Limit = -1
+		}
+		query += "LIMIT ? OFFSET ? "
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get all query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item, err := scanSQLiteSong(rows)
+		if err != nil {
+			log.Printf("method get all scan error: [%s], query: [%s]\n", err.Error(), query)
+			return nil, err
+		}
+		songs = append(songs, item)
+	}
+
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	return songs, rows.Err()
+}
+
+func (s *SQLiteStorage) GetSong(id int) (*song.Song, error) {
+
+	rows, err := s.DB.Query(
+		`SELECT id, song_name, group_name, release_date, release_date_precision, text, link, created_at, updated_at, version FROM songs
+	WHERE id = ? AND deleted_at IS NULL`, id,
+	)
+	if err != nil {
+		log.Printf("method get song query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errSongNotFound(id)
+	}
+
+	item, err := scanSQLiteSong(rows)
+	if err != nil {
+		log.Printf("method get song scan error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *SQLiteStorage) CountSongs(filter song.Filter) (int, bool, error) {
+
+	conditions, args, err := sqliteFilterConditions(filter)
+	if err != nil {
+		return 0, false, err
+	}
+
+	query := "SELECT count(*) FROM songs "
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int
+	err = s.DB.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		log.Printf("method count songs query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return 0, false, err
+	}
+
+	return count, false, nil
+}
+
+// sqliteFilterConditions returns WHERE conditions for filter, filters by album and tags are not supported
+func sqliteFilterConditions(filter song.Filter) ([]string, []interface{}, error) {
+
+	if filter.AlbumID != 0 {
+		return nil, nil, errUnsupportedFilter("album")
+	}
+
+	if len(filter.Tags) > 0 {
+		return nil, nil, errUnsupportedFilter("tags")
+	}
+
+	//songs in trash are never listed
+	conditions := []string{"deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+
+	//instr is used instead of LIKE, because LIKE in sqlite ignores case unlike postgres
+	if filter.SongName != "" {
+		conditions = append(conditions, "instr(song_name, ?) > 0")
+		args = append(args, filter.SongName)
+	}
+
+	if filter.Group != "" {
+		conditions = append(conditions, "instr(group_name, ?) > 0")
+		args = append(args, filter.Group)
+	}
+
+	from, before, err := releaseBounds(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !from.IsZero() {
+		conditions = append(conditions, "release_date >= ?")
+		args = append(args, from.Format(sqliteDateLayout))
+	}
+
+	if !before.IsZero() {
+		conditions = append(conditions, sqliteReleaseDateEnd+" <= ?")
+		args = append(args, before.Format(sqliteDateLayout))
+	}
+
+	if filter.Text != "" {
+		conditions = append(conditions, "instr(text, ?) > 0")
+		args = append(args, filter.Text)
+	}
+
+	if strings.ToLower(filter.Link) == "true" {
+		conditions = append(conditions, "link is not null")
+	} else if strings.ToLower(filter.Link) == "false" {
+		conditions = append(conditions, "link is null")
+	}
+
+	if filter.Query != nil {
+		conditions = append(conditions, queryCondition(filter.Query, sqliteQueryDialect, func(arg interface{}) string {
+			args = append(args, arg)
+			return "?"
+		}))
+	}
+
+	return conditions, args, nil
+}
+
+// sqliteReleaseDateEnd is the day after the last day of release date period
+const sqliteReleaseDateEnd = "date(release_date, CASE release_date_precision WHEN 'year' THEN '+1 year' WHEN 'month' THEN '+1 month' ELSE '+1 day' END)"
+
+// sqliteReleaseDate returns NULL date for empty value, precision of NULL date is day
+func sqliteReleaseDate(value string) (sql.NullString, string, error) {
+
+	if value == "" {
+		return sql.NullString{}, song.PrecisionDay, nil
+	}
+
+	date, err := song.ParseReleaseDate(value)
+	if err != nil {
+		return sql.NullString{}, "", errBadDate
+	}
+
+	return sql.NullString{String: date.Start.Format(sqliteDateLayout), Valid: true}, date.Precision, nil
+}
+
+// scanSQLiteSong reads row with columns id, song_name, group_name, release_date, release_date_precision, text, link,
+// created_at, updated_at, version
+func scanSQLiteSong(rows *sql.Rows, extra ...interface{}) (*song.Song, error) {
+
+	var text, link, date sql.NullString
+	var precision, createdAt, updatedAt string
+	var version int
+	item := &song.Song{}
+
+	err := rows.Scan(append([]interface{}{&item.ID, &item.Name, &item.Group, &date, &precision, &text, &link, &createdAt, &updatedAt, &version}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	item.SetVersion(version)
+
+	item.CreatedAt, err = time.Parse(sqliteTimeLayout, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse creation time of song with id [%d]: %w", item.ID, err)
+	}
+	item.UpdatedAt, err = time.Parse(sqliteTimeLayout, updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse update time of song with id [%d]: %w", item.ID, err)
+	}
+
+	if date.Valid {
+		parsed, err := time.Parse(sqliteDateLayout, date.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse release date of song with id [%d]: %w", item.ID, err)
+		}
+		item.ReleaseDate = song.NewReleaseDate(parsed, precision).String()
+	}
+	item.Text = text.String
+	item.Link = link.String
+
+	return item, nil
+}
+
+func (s *SQLiteStorage) Add(name, group, releaseDate, text, link string) (int, error) {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method add begin transaction error: [%s]\n", err.Error())
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	insertID, err := s.addSong(tx, name, group, releaseDate, text, link)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method add commit error: [%s]\n", err.Error())
+		return 0, err
+	}
+
+	return insertID, nil
+}
+
+// addSong does the same as addSong of postgres Storage
+func (s *SQLiteStorage) addSong(tx *sql.Tx, name, group, releaseDate, text, link string) (int, error) {
+
+	//empty release date is stored as NULL, it is filled later by enrichment
+	var insertID int
+	date, precision, err := sqliteReleaseDate(releaseDate)
+	if err != nil {
+		return 0, err
+	}
+	group, err = sqliteGroupName(tx, group)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO
+	songs(song_name,group_name,release_date,release_date_precision,text,link)
+	VALUES(?,?,?,?,NULLIF(?,''),NULLIF(?,''))
+	RETURNING id`,
+		name, group, date, precision, text, link,
+	).Scan(&insertID)
+
+	if err != nil {
+		err = sqliteError(err, fmt.Sprintf("song [%s] of group [%s]", name, group))
+		if !isMapped(err) {
+			log.Printf("method add query error: [%s], args: [song: %s, group: %s, releaseDate: %s, link: %s]\n", err.Error(), name, group, releaseDate, link)
+		}
+		return 0, err
+	}
+
+	err = sqliteReplaceVerses(tx, insertID, text)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = sqliteRecordRevision(tx, insertID, song.RevisionCreate, s.actor, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return insertID, nil
+}
+
+func (s *SQLiteStorage) Delete(id int) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method delete begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := sqliteSongState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = sqliteCheckVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+
+	//song is moved to trash, its pending enrichment is dropped
+	_, err = tx.Exec(
+		`UPDATE songs SET deleted_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE id = ?`, id,
+	)
+	if err != nil {
+		log.Printf("method delete query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	_, err = tx.Exec(
+		`DELETE FROM enrichment_jobs WHERE song_id = ?`, id,
+	)
+	if err != nil {
+		log.Printf("method delete enrichment job query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	_, err = sqliteRecordRevision(tx, id, song.RevisionDelete, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method delete commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) Update(id int, releaseDate, text, link string) error {
+
+	query := "UPDATE songs SET "
+	sets := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	if releaseDate != "" {
+		date, precision, err := sqliteReleaseDate(releaseDate)
+		if err != nil {
+			return err
+		}
+		sets = append(sets, "release_date = ?", "release_date_precision = ?")
+		args = append(args, date, precision)
+	}
+
+	if text != "" {
+		sets = append(sets, "text = ?")
+		args = append(args, text)
+	}
+
+	if link != "" {
+		sets = append(sets, "link = ?")
+		args = append(args, link)
+	}
+
+	if len(sets) == 0 {
+		return errNoUpdate
+	}
+
+	query += strings.Join(sets, ", ") + " WHERE id = ?"
+	args = append(args, id)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method update begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := sqliteSongState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = sqliteCheckVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		err = sqliteError(err, fmt.Sprintf("song with id [%d]", id))
+		if !isMapped(err) {
+			log.Printf("method update query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		}
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errSongNotFound(id)
+	}
+
+	if text != "" {
+		err = sqliteReplaceVerses(tx, id, text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = sqliteRecordRevision(tx, id, song.RevisionUpdate, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method update commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) GetVerses(id, limit, offset int) ([]*song.Verse, int, error) {
+
+	var total int
+	err := s.DB.QueryRow(
+		`SELECT count(verses.position) FROM songs LEFT JOIN verses ON verses.song_id = songs.id
+	WHERE songs.id = ? AND songs.deleted_at IS NULL
+	GROUP BY songs.id`, id,
+	).Scan(&total)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, errSongNotFound(id)
+		}
+		log.Printf("method get verses count query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, 0, err
+	}
+
+	//-1 means no limit
+	if limit == 0 {
+		limit = -1
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT position, kind, text FROM verses WHERE song_id = ? ORDER BY position LIMIT ? OFFSET ?`,
+		id, limit, offset,
+	)
+	if err != nil {
+		log.Printf("method get verses query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, 0, err
+	}
+
+	verses, err := scanVerses(rows)
+	if err != nil {
+		log.Printf("method get verses scan error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, 0, err
+	}
+
+	return verses, total, nil
+}
+
+func (s *SQLiteStorage) GetVerse(id, position int) (*song.Verse, error) {
+
+	verse := &song.Verse{}
+	err := s.DB.QueryRow(
+		`SELECT position, kind, verses.text FROM verses JOIN songs ON songs.id = verses.song_id
+	WHERE song_id = ? AND position = ? AND songs.deleted_at IS NULL`, id, position,
+	).Scan(&verse.Position, &verse.Kind, &verse.Text)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.verseNotFound(id, position)
+		}
+		log.Printf("method get verse query error: [%s], id: [%d], position: [%d]\n", err.Error(), id, position)
+		return nil, err
+	}
+
+	return verse, nil
+}
+
+func (s *SQLiteStorage) UpdateVerse(id int, verse *song.Verse) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method update verse begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := sqliteSongState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = sqliteCheckVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(
+		`UPDATE verses SET kind = coalesce(NULLIF(?, ''), kind), text = ?
+	WHERE song_id = ? AND position = ?
+	RETURNING kind`,
+		verse.Kind, verse.Text, id, verse.Position,
+	).Scan(&verse.Kind)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			//the only connection is held by transaction
+			tx.Rollback()
+			return s.verseNotFound(id, verse.Position)
+		}
+		err = sqliteError(err, "verse")
+		if !isMapped(err) {
+			log.Printf("method update verse query error: [%s], id: [%d], position: [%d]\n", err.Error(), id, verse.Position)
+		}
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT position, kind, text FROM verses WHERE song_id = ? ORDER BY position`, id)
+	if err != nil {
+		log.Printf("method update verse select query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	verses, err := scanVerses(rows)
+	if err != nil {
+		log.Printf("method update verse scan error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE songs SET text = ? WHERE id = ?`, song.JoinVerses(verses), id)
+	if err != nil {
+		log.Printf("method update verse song text query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	_, err = sqliteRecordRevision(tx, id, song.RevisionUpdate, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method update verse commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// verseNotFound tells missing song from missing verse of existing song
+func (s *SQLiteStorage) verseNotFound(id, position int) error {
+
+	var exists bool
+	err := s.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM songs WHERE id = ? AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		log.Printf("song exists query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+	if !exists {
+		return errSongNotFound(id)
+	}
+
+	return errVerseNotFound(id, position)
+}
+
+// sqliteReplaceVerses stores verses parsed from text instead of current verses of song
+func sqliteReplaceVerses(tx *sql.Tx, id int, text string) error {
+
+	_, err := tx.Exec(`DELETE FROM verses WHERE song_id = ?`, id)
+	if err != nil {
+		log.Printf("replace verses delete query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	for _, verse := range song.ParseVerses(text) {
+		_, err = tx.Exec(
+			`INSERT INTO verses(song_id,position,kind,text) VALUES(?,?,?,?)`,
+			id, verse.Position, verse.Kind, verse.Text,
+		)
+		if err != nil {
+			log.Printf("replace verses insert query error: [%s], id: [%d]\n", err.Error(), id)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) EnqueueEnrichment(songID int) (*song.Enrichment, error) {
+	return sqliteEnqueueEnrichment(s.DB, songID)
+}
+
+// AddWithEnrichment adds song and its pending enrichment job in one transaction
+func (s *SQLiteStorage) AddWithEnrichment(name, group, releaseDate, text, link string) (int, *song.Enrichment, error) {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method add with enrichment begin transaction error: [%s]\n", err.Error())
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	insertID, err := s.addSong(tx, name, group, releaseDate, text, link)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	enrichment, err := sqliteEnqueueEnrichment(tx, insertID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method add with enrichment commit error: [%s]\n", err.Error())
+		return 0, nil, err
+	}
+
+	return insertID, enrichment, nil
+}
+
+func sqliteEnqueueEnrichment(q rowQuerier, songID int) (*song.Enrichment, error) {
+
+	now := time.Now().UTC().Format(sqliteTimeLayout)
+	enrichment, err := scanSQLiteEnrichment(q.QueryRow(
+		`INSERT INTO enrichment_jobs(song_id,next_run_at,created_at,updated_at)
+	SELECT id, ?, ?, ? FROM songs WHERE id = ? AND deleted_at IS NULL
+	ON CONFLICT (song_id) DO UPDATE SET status = 'pending', attempts = 0, last_error = NULL, next_run_at = excluded.next_run_at, updated_at = excluded.updated_at
+	RETURNING song_id, status, attempts, last_error, next_run_at, updated_at`,
+		now, now, now, songID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errSongNotFound(songID)
+		}
+		log.Printf("method enqueue enrichment query error: [%s], song id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	return enrichment, nil
+}
+
+func (s *SQLiteStorage) GetEnrichment(songID int) (*song.Enrichment, error) {
+
+	enrichment, err := scanSQLiteEnrichment(s.DB.QueryRow(
+		`SELECT song_id, status, attempts, last_error, next_run_at, updated_at FROM enrichment_jobs WHERE song_id = ?`, songID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errEnrichmentNotFound(songID)
+		}
+		log.Printf("method get enrichment query error: [%s], song id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	return enrichment, nil
+}
+
+// ClaimEnrichment relies on the only connection to sqlite, so update with subquery is atomic
+func (s *SQLiteStorage) ClaimEnrichment(staleAfter time.Duration) (*enrich.Job, error) {
+
+	now := time.Now().UTC()
+	job := &enrich.Job{}
+	err := s.DB.QueryRow(
+		`UPDATE enrichment_jobs SET status = 'running', attempts = attempts + 1, updated_at = ?
+	WHERE song_id = (
+		SELECT song_id FROM enrichment_jobs
+		WHERE (status = 'pending' AND next_run_at <= ?)
+		OR (status = 'running' AND updated_at <= ?)
+		ORDER BY next_run_at
+		LIMIT 1
+	)
+	RETURNING song_id, attempts, (SELECT song_name FROM songs WHERE songs.id = enrichment_jobs.song_id), (SELECT group_name FROM songs WHERE songs.id = enrichment_jobs.song_id)`,
+		now.Format(sqliteTimeLayout), now.Format(sqliteTimeLayout), now.Add(-staleAfter).Format(sqliteTimeLayout),
+	).Scan(&job.SongID, &job.Attempts, &job.Song, &job.Group)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("method claim enrichment query error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *SQLiteStorage) CompleteEnrichment(songID int) error {
+
+	err := s.finishEnrichment(songID, song.EnrichmentDone, time.Now(), "")
+	if err != nil {
+		return err
+	}
+
+	return s.MarkEnriched(songID)
+}
+
+func (s *SQLiteStorage) RetryEnrichment(songID int, nextRunAt time.Time, reason string) error {
+	return s.finishEnrichment(songID, song.EnrichmentPending, nextRunAt, reason)
+}
+
+func (s *SQLiteStorage) FailEnrichment(songID int, reason string) error {
+	return s.finishEnrichment(songID, song.EnrichmentFailed, time.Now(), reason)
+}
+
+func (s *SQLiteStorage) finishEnrichment(songID int, status string, nextRunAt time.Time, reason string) error {
+
+	_, err := s.DB.Exec(
+		`UPDATE enrichment_jobs SET status = ?, next_run_at = ?, last_error = NULLIF(?, ''), updated_at = ?
+	WHERE song_id = ? AND status = 'running'`,
+		status, nextRunAt.UTC().Format(sqliteTimeLayout), reason, time.Now().UTC().Format(sqliteTimeLayout), songID,
+	)
+	if err != nil {
+		log.Printf("method finish enrichment query error: [%s], song id: [%d], status: [%s]\n", err.Error(), songID, status)
+		return err
+	}
+
+	return nil
+}
+
+func scanSQLiteEnrichment(row rowScanner) (*song.Enrichment, error) {
+
+	var lastError sql.NullString
+	var nextRunAt, updatedAt string
+	enrichment := &song.Enrichment{}
+
+	err := row.Scan(&enrichment.SongID, &enrichment.Status, &enrichment.Attempts, &lastError, &nextRunAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	enrichment.LastError = lastError.String
+
+	enrichment.NextRunAt, err = time.Parse(sqliteTimeLayout, nextRunAt)
+	if err != nil {
+		return nil, err
+	}
+	enrichment.UpdatedAt, err = time.Parse(sqliteTimeLayout, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return enrichment, nil
+}
+
+func (s *SQLiteStorage) GetStaleSongs(enrichedBefore, incompleteBefore time.Time, limit int) ([]*song.Song, error) {
+
+	rows, err := s.DB.Query(
+		`SELECT id, song_name, group_name, release_date, release_date_precision, text, link, created_at, updated_at, version FROM songs
+	WHERE (last_enriched_at IS NULL OR last_enriched_at < ?
+		OR (last_enriched_at < ? AND (release_date IS NULL OR coalesce(text, '') = '' OR coalesce(link, '') = '')))
+	AND deleted_at IS NULL
+	AND NOT EXISTS (
+		SELECT 1 FROM enrichment_jobs WHERE enrichment_jobs.song_id = songs.id AND enrichment_jobs.status IN ('pending', 'running')
+	)
+	ORDER BY last_enriched_at NULLS FIRST, id
+	LIMIT ?`,
+		enrichedBefore.UTC().Format(sqliteTimeLayout), incompleteBefore.UTC().Format(sqliteTimeLayout), limit,
+	)
+	if err != nil {
+		log.Printf("method get stale songs query error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]*song.Song, 0)
+	for rows.Next() {
+		item, err := scanSQLiteSong(rows)
+		if err != nil {
+			log.Printf("method get stale songs scan error: [%s]\n", err.Error())
+			return nil, err
+		}
+		songs = append(songs, item)
+	}
+
+	return songs, rows.Err()
+}
+
+func (s *SQLiteStorage) MarkEnriched(songID int) error {
+
+	_, err := s.DB.Exec(`UPDATE songs SET last_enriched_at = ? WHERE id = ?`, time.Now().UTC().Format(sqliteTimeLayout), songID)
+	if err != nil {
+		log.Printf("method mark enriched query error: [%s], song id: [%d]\n", err.Error(), songID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) SaveRefreshRun(run *enrich.RefreshRun) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method save refresh run begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`INSERT INTO refresh_runs(started_at,finished_at,checked,updated,failed) VALUES(?,?,?,?,?) RETURNING id`,
+		run.StartedAt.UTC().Format(sqliteTimeLayout), run.FinishedAt.UTC().Format(sqliteTimeLayout), run.Checked, run.Updated, run.Failed,
+	).Scan(&run.ID)
+	if err != nil {
+		log.Printf("method save refresh run query error: [%s]\n", err.Error())
+		return err
+	}
+
+	for _, change := range run.Changes {
+		_, err = tx.Exec(
+			`INSERT INTO refresh_changes(run_id,song_id,field,old_value,new_value) VALUES(?,?,?,?,?)`,
+			run.ID, change.SongID, change.Field, change.OldValue, change.NewValue,
+		)
+		if err != nil {
+			log.Printf("method save refresh run change query error: [%s], run id: [%d]\n", err.Error(), run.ID)
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method save refresh run commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) GetRefreshRuns(limit, offset int) ([]*enrich.RefreshRun, error) {
+
+	//sqlite does not accept OFFSET without LIMIT, -1 means no limit
+	if limit == 0 {
+		limit = -1
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT id, started_at, finished_at, checked, updated, failed FROM refresh_runs ORDER BY id DESC LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		log.Printf("method get refresh runs query error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	runs, err := scanSQLiteRefreshRuns(rows)
+	if err != nil {
+		log.Printf("method get refresh runs scan error: [%s]\n", err.Error())
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return runs, nil
+	}
+
+	rows, err = s.DB.Query(
+		`SELECT run_id, song_id, field, old_value, new_value FROM refresh_changes WHERE run_id BETWEEN ? AND ? ORDER BY id`,
+		runs[len(runs)-1].ID, runs[0].ID,
+	)
+	if err != nil {
+		log.Printf("method get refresh runs changes query error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	err = scanRefreshChanges(rows, runs)
+	if err != nil {
+		log.Printf("method get refresh runs changes scan error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func scanSQLiteRefreshRuns(rows *sql.Rows) ([]*enrich.RefreshRun, error) {
+
+	defer rows.Close()
+
+	runs := make([]*enrich.RefreshRun, 0)
+	for rows.Next() {
+		var startedAt, finishedAt string
+		run := &enrich.RefreshRun{
+			Changes: make([]*enrich.RefreshChange, 0),
+		}
+
+		err := rows.Scan(&run.ID, &startedAt, &finishedAt, &run.Checked, &run.Updated, &run.Failed)
+		if err != nil {
+			return nil, err
+		}
+
+		run.StartedAt, err = time.Parse(sqliteTimeLayout, startedAt)
+		if err != nil {
+			return nil, err
+		}
+		run.FinishedAt, err = time.Parse(sqliteTimeLayout, finishedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// ImportSongs uses savepoint for every song like postgres Storage
+func (s *SQLiteStorage) ImportSongs(songs []*song.Song, onDuplicate string) ([]*song.ImportResult, error) {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method import songs begin transaction error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]*song.ImportResult, 0, len(songs))
+	for _, item := range songs {
+		result := &song.ImportResult{
+			Song:  item.Name,
+			Group: item.Group,
+		}
+		results = append(results, result)
+
+		_, err = tx.Exec(`SAVEPOINT import_song`)
+		if err != nil {
+			log.Printf("method import songs savepoint error: [%s]\n", err.Error())
+			return nil, err
+		}
+
+		err = sqliteImportSong(tx, item, onDuplicate, s.actor, result)
+		if err != nil {
+			if !isMapped(err) {
+				log.Printf("method import songs query error: [%s], song: [%s], group: [%s]\n", err.Error(), item.Name, item.Group)
+			}
+			result.Status = song.ImportFailed
+			result.ID = 0
+			result.Error = err.Error()
+
+			_, err = tx.Exec(`ROLLBACK TO SAVEPOINT import_song`)
+			if err != nil {
+				log.Printf("method import songs rollback to savepoint error: [%s]\n", err.Error())
+				return nil, err
+			}
+		}
+
+		_, err = tx.Exec(`RELEASE SAVEPOINT import_song`)
+		if err != nil {
+			log.Printf("method import songs release savepoint error: [%s]\n", err.Error())
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method import songs commit error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func sqliteImportSong(tx *sql.Tx, item *song.Song, onDuplicate, actor string, result *song.ImportResult) error {
+
+	date, precision, err := sqliteReleaseDate(item.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	group, err := sqliteGroupName(tx, item.Group)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("song [%s] of group [%s]", item.Name, group)
+	err = tx.QueryRow(
+		`INSERT INTO songs(song_name,group_name,release_date,release_date_precision,text,link)
+	VALUES(?,?,?,?,NULLIF(?,''),NULLIF(?,''))
+	ON CONFLICT (song_name, lower(group_name)) WHERE deleted_at IS NULL DO NOTHING
+	RETURNING id`,
+		item.Name, group, date, precision, item.Text, item.Link,
+	).Scan(&result.ID)
+	if err == nil {
+		result.Status = song.ImportCreated
+		err = sqliteReplaceVerses(tx, result.ID, item.Text)
+		if err != nil {
+			return err
+		}
+		_, err = sqliteRecordRevision(tx, result.ID, song.RevisionCreate, actor, nil)
+		return err
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return sqliteError(err, subject)
+	}
+
+	if onDuplicate == song.DuplicateFail {
+		return errSongExists(item.Name, group)
+	}
+
+	err = tx.QueryRow(`SELECT id FROM songs WHERE song_name = ? AND group_name = ? AND deleted_at IS NULL`, item.Name, group).Scan(&result.ID)
+	if err != nil {
+		return err
+	}
+
+	if onDuplicate == song.DuplicateSkip || (!date.Valid && item.Text == "" && item.Link == "") {
+		result.Status = song.ImportSkipped
+		return nil
+	}
+
+	previous, err := sqliteSongState(tx, result.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE songs SET release_date = coalesce(?1, release_date),
+		release_date_precision = CASE WHEN ?1 IS NULL THEN release_date_precision ELSE ?2 END,
+		text = coalesce(NULLIF(?3, ''), text), link = coalesce(NULLIF(?4, ''), link)
+	WHERE id = ?5`,
+		date, precision, item.Text, item.Link, result.ID,
+	)
+	if err != nil {
+		return sqliteError(err, subject)
+	}
+
+	if item.Text != "" {
+		err = sqliteReplaceVerses(tx, result.ID, item.Text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = sqliteRecordRevision(tx, result.ID, song.RevisionUpdate, actor, previous)
+	if err != nil {
+		return err
+	}
+	result.Status = song.ImportUpdated
+
+	return nil
+}
+
+// As returns storage which records revisions with actor, it shares connection with s
+func (s *SQLiteStorage) As(actor string) song.Storage {
+	res := *s
+	res.actor = actor
+	return &res
+}
+
+// IfVersion returns storage which changes song only with given version, it shares connection with s
+func (s *SQLiteStorage) IfVersion(version int) song.Storage {
+	res := *s
+	res.ifVersion = version
+	return &res
+}
+
+// sqliteCheckVersion does the same as checkVersion
+// sqliteGroupName returns spelling of group stored with other songs, groups which differ only in case are the same group
+// like in postgres. lower of sqlite changes only ASCII letters, so other letters are compared with case
+func sqliteGroupName(q rowQuerier, group string) (string, error) {
+
+	var stored string
+	err := q.QueryRow(`SELECT group_name FROM songs WHERE lower(group_name) = lower(?) LIMIT 1`, group).Scan(&stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return group, nil
+	}
+	if err != nil {
+		log.Printf("group name query error: [%s], group: [%s]\n", err.Error(), group)
+		return "", err
+	}
+
+	return stored, nil
+}
+
+func sqliteCheckVersion(tx *sql.Tx, id, expected int) error {
+
+	if expected == 0 {
+		return nil
+	}
+
+	var version int
+	err := tx.QueryRow(`SELECT version FROM songs WHERE id = ?`, id).Scan(&version)
+	if err != nil {
+		log.Printf("song version query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+	if version != expected {
+		return errVersionChanged(id, version)
+	}
+
+	return nil
+}
+
+// sqliteSongState does the same as songState, the only connection is held by tx, so no lock is needed
+func sqliteSongState(tx *sql.Tx, id int) (*song.SongState, error) {
+
+	var date, text, link sql.NullString
+	var precision string
+	state := &song.SongState{}
+
+	err := tx.QueryRow(
+		`SELECT song_name, group_name, release_date, release_date_precision, text, link FROM songs WHERE id = ? AND deleted_at IS NULL`, id,
+	).Scan(&state.Name, &state.Group, &date, &precision, &text, &link)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("song state query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, err
+	}
+
+	if date.Valid {
+		parsed, err := time.Parse(sqliteDateLayout, date.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse release date of song with id [%d]: %w", id, err)
+		}
+		state.ReleaseDate = song.NewReleaseDate(parsed, precision).String()
+	}
+	state.Text = text.String
+	state.Link = link.String
+
+	return state, nil
+}
+
+// sqliteRecordRevision does the same as recordRevision
+func sqliteRecordRevision(tx *sql.Tx, id int, action, actor string, previous *song.SongState) (*song.Revision, error) {
+
+	var state *song.SongState
+	if action != song.RevisionDelete {
+		var err error
+		state, err = sqliteSongState(tx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	oldState, err := marshalState(previous)
+	if err != nil {
+		return nil, err
+	}
+	newState, err := marshalState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisionID int
+	var createdAt string
+	actor = revisionActor(actor)
+	err = tx.QueryRow(
+		`INSERT INTO song_revisions(song_id,action,actor,old_state,new_state)
+	VALUES(?,?,?,?,?)
+	RETURNING id, created_at`,
+		id, action, actor, oldState, newState,
+	).Scan(&revisionID, &createdAt)
+	if err != nil {
+		log.Printf("record revision query error: [%s], id: [%d], action: [%s]\n", err.Error(), id, action)
+		return nil, err
+	}
+
+	created, err := time.Parse(sqliteTimeLayout, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("parse creation time of revision [%d]: %w", revisionID, err)
+	}
+
+	return song.NewRevision(revisionID, id, action, actor, created, previous, state), nil
+}
+
+func (s *SQLiteStorage) GetRevisions(songID, limit, offset int) ([]*song.Revision, error) {
+
+	//sqlite does not accept OFFSET without LIMIT, -1 means no limit
+	if limit == 0 {
+		limit = -1
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT id, song_id, action, actor, created_at, old_state, new_state FROM song_revisions
+	WHERE song_id = ? ORDER BY id DESC LIMIT ? OFFSET ?`,
+		songID, limit, offset,
+	)
+	if err != nil {
+		log.Printf("method get revisions query error: [%s], id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	revisions, err := scanRevisions(rows)
+	if err != nil {
+		log.Printf("method get revisions scan error: [%s], id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (s *SQLiteStorage) GetRevision(songID, revisionID int) (*song.Revision, error) {
+
+	rows, err := s.DB.Query(
+		`SELECT id, song_id, action, actor, created_at, old_state, new_state FROM song_revisions
+	WHERE song_id = ? AND id = ?`,
+		songID, revisionID,
+	)
+	if err != nil {
+		log.Printf("method get revision query error: [%s], id: [%d], revision: [%d]\n", err.Error(), songID, revisionID)
+		return nil, err
+	}
+
+	revisions, err := scanRevisions(rows)
+	if err != nil {
+		log.Printf("method get revision scan error: [%s], id: [%d], revision: [%d]\n", err.Error(), songID, revisionID)
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, errRevisionNotFound(songID, revisionID)
+	}
+
+	return revisions[0], nil
+}
+
+func (s *SQLiteStorage) RestoreRevision(songID, revisionID int) (*song.Revision, error) {
+
+	target, err := s.GetRevision(songID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if target.State == nil {
+		return nil, errRestoreDeleted(revisionID)
+	}
+
+	date, precision, err := sqliteReleaseDate(target.State.ReleaseDate)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method restore revision begin transaction error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	previous, err := sqliteSongState(tx, songID)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return nil, errSongNotFound(songID)
+	}
+	err = sqliteCheckVersion(tx, songID, s.ifVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := sqliteGroupName(tx, target.State.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE songs SET song_name = ?, group_name = ?, release_date = ?, release_date_precision = ?,
+	text = NULLIF(?, ''), link = NULLIF(?, '')
+	WHERE id = ?`,
+		target.State.Name, group, date, precision, target.State.Text, target.State.Link, songID,
+	)
+	if err != nil {
+		err = sqliteError(err, fmt.Sprintf("song [%s] of group [%s]", target.State.Name, target.State.Group))
+		if !isMapped(err) {
+			log.Printf("method restore revision query error: [%s], id: [%d], revision: [%d]\n", err.Error(), songID, revisionID)
+		}
+		return nil, err
+	}
+
+	err = sqliteReplaceVerses(tx, songID, target.State.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := sqliteRecordRevision(tx, songID, song.RevisionRestore, s.actor, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method restore revision commit error: [%s]\n", err.Error())
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+func (s *SQLiteStorage) GetTrash(limit, offset int) ([]*song.TrashedSong, error) {
+
+	//-1 means no limit
+	if limit == 0 {
+		limit = -1
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT id, song_name, group_name, release_date, release_date_precision, text, link, created_at, updated_at, version, deleted_at FROM songs
+	WHERE deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC, id DESC
+	LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		log.Printf("method get trash query error: [%s]\n", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]*song.TrashedSong, 0)
+	for rows.Next() {
+		var deletedAt string
+		item, err := scanSQLiteSong(rows, &deletedAt)
+		if err != nil {
+			log.Printf("method get trash scan error: [%s]\n", err.Error())
+			return nil, err
+		}
+
+		trashed := &song.TrashedSong{Song: *item}
+		trashed.DeletedAt, err = time.Parse(sqliteTimeLayout, deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse delete time of song with id [%d]: %w", item.ID, err)
+		}
+		songs = append(songs, trashed)
+	}
+
+	return songs, rows.Err()
+}
+
+func (s *SQLiteStorage) RestoreTrashed(id int) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method restore trashed begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE songs SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	)
+	if err != nil {
+		err = sqliteError(err, fmt.Sprintf("song with id [%d]", id))
+		if !isMapped(err) {
+			log.Printf("method restore trashed query error: [%s], id: [%d]\n", err.Error(), id)
+		}
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errTrashedNotFound(id)
+	}
+
+	_, err = sqliteRecordRevision(tx, id, song.RevisionRestore, s.actor, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method restore trashed commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) Purge(id int) error {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM songs WHERE id = ? AND deleted_at IS NOT NULL`, id,
+	)
+	if err != nil {
+		log.Printf("method purge query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errTrashedNotFound(id)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) PurgeTrash(deletedBefore time.Time) (int, error) {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM songs WHERE deleted_at < ?`, deletedBefore.UTC().Format(sqliteTimeLayout),
+	)
+	if err != nil {
+		log.Printf("method purge trash query error: [%s], deleted before: [%s]\n", err.Error(), deletedBefore)
+		return 0, err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(num), nil
+}
+
+// Patch does the same as Storage.Patch
+func (s *SQLiteStorage) Patch(id int, patch *song.SongPatch) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method patch begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := sqliteSongState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = sqliteCheckVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+	if patch.Empty() {
+		return nil
+	}
+
+	query := "UPDATE songs SET "
+	args := make([]interface{}, 0)
+
+	if patch.Name != nil {
+		query += "song_name = ?, "
+		args = append(args, *patch.Name)
+	}
+
+	if patch.Group != nil {
+		group, err := sqliteGroupName(tx, *patch.Group)
+		if err != nil {
+			return err
+		}
+		query += "group_name = ?, "
+		args = append(args, group)
+	}
+
+	if patch.ReleaseDate != nil {
+		date, precision, err := sqliteReleaseDate(*patch.ReleaseDate)
+		if err != nil {
+			return err
+		}
+		query += "release_date = ?, release_date_precision = ?, "
+		args = append(args, date, precision)
+	}
+
+	if patch.Text != nil {
+		query += "text = NULLIF(?, ''), "
+		args = append(args, *patch.Text)
+	}
+
+	if patch.Link != nil {
+		query += "link = NULLIF(?, ''), "
+		args = append(args, *patch.Link)
+	}
+
+	query = strings.TrimSuffix(query, ", ") + " WHERE id = ?"
+	args = append(args, id)
+
+	_, err = tx.Exec(query, args...)
+	if err != nil {
+		err = sqliteError(err, patchSubject(previous, patch))
+		if !isMapped(err) {
+			log.Printf("method patch query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		}
+		return err
+	}
+
+	if patch.Text != nil {
+		err = sqliteReplaceVerses(tx, id, *patch.Text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = sqliteRecordRevision(tx, id, song.RevisionUpdate, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method patch commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
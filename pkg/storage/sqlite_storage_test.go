@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/storage/storagetest"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// openTestSQLite returns connection to new database file in temp dir, migrations are applied, reverted and
+// applied again, so down migrations and tables rebuilt by them are checked by every test
+func openTestSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "songlibrary.db")
+	migration, err := migrate.New("file://../../migration/sqlite", "sqlite://"+path)
+	if err != nil {
+		t.Fatalf("create migration error: %v", err)
+	}
+	defer migration.Close()
+
+	for _, step := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"up", migration.Up},
+		{"down", migration.Down},
+		{"up after down", migration.Up},
+	} {
+		err = step.fn()
+		if err != nil {
+			t.Fatalf("migration %s error: %v", step.name, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite error: %v", err)
+	}
+	//the same as in main, sqlite allows only one writer at a time
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestSQLiteStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) song.Storage {
+		return NewSQLiteStorage(openTestSQLite(t))
+	})
+}
@@ -0,0 +1,1187 @@
+// Package storagetest contains conformance checks shared by all song.Storage implementations.
+//
+// Backend tests call Run with a constructor that returns an empty storage, e.g.
+//
+//	storagetest.Run(t, func(t *testing.T) song.Storage { return storage.NewMemoryStorage() })
+package storagetest
+
+import (
+	"SongLibrary/pkg/song"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Run executes every conformance check in its own subtest, newStorage must return empty storage on every call
+func Run(t *testing.T, newStorage func(t *testing.T) song.Storage) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s song.Storage)
+	}{
+		{"AddAndGet", testAddAndGet},
+		{"AddDuplicate", testAddDuplicate},
+		{"GroupCase", testGroupCase},
+		{"AddBadDate", testAddBadDate},
+		{"AddWithoutReleaseDate", testAddWithoutReleaseDate},
+		{"GetNoRows", testGetNoRows},
+		{"GetAllFragments", testGetAllFragments},
+		{"GetAllYear", testGetAllYear},
+		{"GetAllBadYear", testGetAllBadYear},
+		{"GetAllLink", testGetAllLink},
+		{"GetAllLimitOffset", testGetAllLimitOffset},
+		{"GetAllReleasePeriod", testGetAllReleasePeriod},
+		{"GetAllQuery", testGetAllQuery},
+		{"GetAllSort", testGetAllSort},
+		{"GetAllSortCursor", testGetAllSortCursor},
+		{"GetAllMultiKeyCursor", testGetAllMultiKeyCursor},
+		{"GetAllTamperedCursor", testGetAllTamperedCursor},
+		{"UpdatePartial", testUpdatePartial},
+		{"UpdateErrors", testUpdateErrors},
+		{"Delete", testDelete},
+		{"VersesParsed", testVersesParsed},
+		{"VersesPagination", testVersesPagination},
+		{"VersesReplacedOnUpdate", testVersesReplacedOnUpdate},
+		{"UpdateVerse", testUpdateVerse},
+		{"VerseNotFound", testVerseNotFound},
+		{"Revisions", testRevisions},
+		{"RestoreRenamed", testRestoreRenamed},
+		{"Trash", testTrash},
+		{"Patch", testPatch},
+		{"Version", testVersion},
+		{"VersionNoChange", testVersionNoChange},
+		{"AddWithEnrichment", testAddWithEnrichment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+func mustAdd(t *testing.T, s song.Storage, name, group, releaseDate, text, link string) int {
+	t.Helper()
+	id, err := s.Add(name, group, releaseDate, text, link)
+	if err != nil {
+		t.Fatalf("add [%s - %s] error: %v", group, name, err)
+	}
+	return id
+}
+
+func fill(t *testing.T, s song.Storage) []int {
+	t.Helper()
+	return []int{
+		mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "line1\nline2\n\nline3", ""),
+		mustAdd(t, s, "diamonds", "rihanna", "27.09.2012", "shine bright\n\nlike a diamond", ""),
+		mustAdd(t, s, "supermassive black hole", "muse", "16.07.2006", "Ooh baby\n\nOoh", "https://www.youtube.com/watch?v=Xsp3_a-PMTw"),
+		mustAdd(t, s, "radioactive", "imagine dragons", "29.10.2012", "welcome to the new age", "https://www.youtube.com/watch?v=ktvTqknDobU"),
+	}
+}
+
+func ids(songs []*song.Song) []int {
+	res := make([]int, 0, len(songs))
+	for _, item := range songs {
+		res = append(res, item.ID)
+	}
+	return res
+}
+
+// withoutTimes returns copy of song with zero creation and update times and version, they are set by storage
+func withoutTimes(item *song.Song) song.Song {
+	res := *item
+	res.CreatedAt = time.Time{}
+	res.UpdatedAt = time.Time{}
+	res.Version = 0
+	res.ETag = ""
+	return res
+}
+
+func equalIDs(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func checkValidation(t *testing.T, err error, field string) {
+	t.Helper()
+	var validationErr *song.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got %v, want validation error", err)
+	}
+	if validationErr.Field != field {
+		t.Errorf("validation error field: got %q, want %q", validationErr.Field, field)
+	}
+	if !errors.Is(err, song.ErrValidation) {
+		t.Errorf("errors.Is(%v, ErrValidation) = false", err)
+	}
+}
+
+func testAddAndGet(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "line1\n\nline2", "some link")
+
+	verses, total, err := s.GetVerses(id, 0, 0)
+	if err != nil {
+		t.Fatalf("get verses error: %v", err)
+	}
+	if total != 2 || len(verses) != 2 || verses[0].Text != "line1" || verses[1].Text != "line2" {
+		t.Errorf("get verses: got %d verses %v", total, verses)
+	}
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if len(songs) != 1 {
+		t.Fatalf("get all: got %d songs, want 1", len(songs))
+	}
+	want := song.Song{ID: id, Name: "demons", Group: "imagine dragons", ReleaseDate: "28.01.2013", Text: "line1\n\nline2", Link: "some link"}
+	if withoutTimes(songs[0]) != want {
+		t.Errorf("get all: got %+v, want %+v", *songs[0], want)
+	}
+
+	item, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if *item != *songs[0] {
+		t.Errorf("get song: got %+v, want %+v", *item, *songs[0])
+	}
+}
+
+func testAddDuplicate(t *testing.T, s song.Storage) {
+	mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "", "")
+
+	_, err := s.Add("demons", "imagine dragons", "01.01.2020", "other", "")
+	if !errors.Is(err, song.ErrConflict) {
+		t.Fatalf("duplicate add: got %v, want %v", err, song.ErrConflict)
+	}
+
+	mustAdd(t, s, "demons", "other group", "28.01.2013", "", "")
+}
+
+// testGroupCase checks that groups which differ only in case are the same group, the first spelling is kept
+func testGroupCase(t *testing.T, s song.Storage) {
+	value := func(v string) *string { return &v }
+
+	mustAdd(t, s, "uprising", "Muse", "", "", "")
+	_, err := s.Add("uprising", "muse", "", "", "")
+	if !errors.Is(err, song.ErrConflict) {
+		t.Fatalf("add to group in other case: got %v, want %v", err, song.ErrConflict)
+	}
+
+	id := mustAdd(t, s, "starlight", "MUSE", "", "", "")
+	stored, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if stored.Group != "Muse" {
+		t.Errorf("group is %s, want stored spelling Muse", stored.Group)
+	}
+
+	if patcher, ok := s.(song.Patcher); ok {
+		other := mustAdd(t, s, "hysteria", "other group", "", "", "")
+		err = patcher.Patch(other, &song.SongPatch{Group: value("mUSE")})
+		if err != nil {
+			t.Fatalf("patch error: %v", err)
+		}
+		stored, err = s.GetSong(other)
+		if err != nil {
+			t.Fatalf("get song error: %v", err)
+		}
+		if stored.Group != "Muse" {
+			t.Errorf("patched group is %s, want stored spelling Muse", stored.Group)
+		}
+
+		err = patcher.Patch(other, &song.SongPatch{Name: value("uprising")})
+		if !errors.Is(err, song.ErrConflict) {
+			t.Fatalf("patch to existing song: got %v, want %v", err, song.ErrConflict)
+		}
+	}
+
+	if importer, ok := s.(song.Importer); ok {
+		results, err := importer.ImportSongs([]*song.Song{
+			{Name: "uprising", Group: "muse"},
+			{Name: "resistance", Group: "muse"},
+		}, song.DuplicateSkip)
+		if err != nil {
+			t.Fatalf("import error: %v", err)
+		}
+		if len(results) != 2 || results[0].Status != song.ImportSkipped || results[1].Status != song.ImportCreated {
+			t.Fatalf("import results: got %+v, want skipped and created", results)
+		}
+		stored, err = s.GetSong(results[1].ID)
+		if err != nil {
+			t.Fatalf("get song error: %v", err)
+		}
+		if stored.Group != "Muse" {
+			t.Errorf("imported group is %s, want stored spelling Muse", stored.Group)
+		}
+	}
+}
+
+func testAddBadDate(t *testing.T, s song.Storage) {
+	_, err := s.Add("demons", "imagine dragons", "2013-01-28", "", "")
+	checkValidation(t, err, "releaseDate")
+}
+
+func testAddWithoutReleaseDate(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "", "", "")
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	want := song.Song{ID: id, Name: "demons", Group: "imagine dragons"}
+	if len(songs) != 1 || withoutTimes(songs[0]) != want {
+		t.Fatalf("got %v, want %+v", songs, want)
+	}
+
+	songs, err = s.GetAll(song.Filter{Year: "2013"})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if len(songs) != 0 {
+		t.Errorf("song without release date must not match year filter, got %v", ids(songs))
+	}
+}
+
+func testGetNoRows(t *testing.T, s song.Storage) {
+	_, err := s.GetSong(100500)
+	if !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("get missing song: got %v, want %v", err, song.ErrNotFound)
+	}
+	_, _, err = s.GetVerses(100500, 0, 0)
+	if !errors.Is(err, song.ErrNotFound) {
+		t.Fatalf("get missing song: got %v, want %v", err, song.ErrNotFound)
+	}
+}
+
+func testGetAllFragments(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+
+	tests := []struct {
+		name                  string
+		songName, group, text string
+		want                  []int
+	}{
+		{"song", "mon", "", "", []int{all[0], all[1]}},
+		{"group", "", "dragons", "", []int{all[0], all[3]}},
+		{"text", "", "", "Ooh", []int{all[2]}},
+		{"combined", "de", "dragons", "line", []int{all[0]}},
+		{"case sensitive", "Demons", "", "", []int{}},
+	}
+
+	for _, tt := range tests {
+		songs, err := s.GetAll(song.Filter{SongName: tt.songName, Group: tt.group, Text: tt.text})
+		if err != nil {
+			t.Fatalf("%s: get all error: %v", tt.name, err)
+		}
+		if got := ids(songs); !equalIDs(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testGetAllYear(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+
+	songs, err := s.GetAll(song.Filter{Year: "2012"})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{all[1], all[3]}; !equalIDs(got, want) {
+		t.Errorf("year 2012: got %v, want %v", got, want)
+	}
+
+	songs, err = s.GetAll(song.Filter{Year: "1999"})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if len(songs) != 0 {
+		t.Errorf("year 1999: got %v, want none", ids(songs))
+	}
+}
+
+func testGetAllBadYear(t *testing.T, s song.Storage) {
+	_, err := s.GetAll(song.Filter{Year: "13.2012"})
+	checkValidation(t, err, "releaseDate")
+}
+
+func testGetAllLink(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+
+	songs, err := s.GetAll(song.Filter{Link: "true"})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{all[2], all[3]}; !equalIDs(got, want) {
+		t.Errorf("link=true: got %v, want %v", got, want)
+	}
+
+	songs, err = s.GetAll(song.Filter{Link: "false"})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{all[0], all[1]}; !equalIDs(got, want) {
+		t.Errorf("link=false: got %v, want %v", got, want)
+	}
+}
+
+func testGetAllLimitOffset(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+
+	tests := []struct {
+		limit, offset int
+		want          []int
+	}{
+		{0, 0, all},
+		{2, 0, all[:2]},
+		{2, 1, all[1:3]},
+		{0, 3, all[3:]},
+		{10, 10, []int{}},
+	}
+
+	for _, tt := range tests {
+		songs, err := s.GetAll(song.Filter{Limit: tt.limit, Offset: tt.offset})
+		if err != nil {
+			t.Fatalf("get all error: %v", err)
+		}
+		if got := ids(songs); !equalIDs(got, tt.want) {
+			t.Errorf("limit %d offset %d: got %v, want %v", tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+// testGetAllReleasePeriod checks that songs known to month or year keep their precision
+// and match period filters only when the whole known period is within them
+func testGetAllReleasePeriod(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+	month := mustAdd(t, s, "bones", "imagine dragons", "03.2022", "", "")
+	year := mustAdd(t, s, "uprising", "muse", "2009", "", "")
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if songs[4].ReleaseDate != "03.2022" || songs[5].ReleaseDate != "2009" {
+		t.Errorf("release dates: got %q and %q, want 03.2022 and 2009", songs[4].ReleaseDate, songs[5].ReleaseDate)
+	}
+
+	period := func(value string) (time.Time, time.Time) {
+		start, end, err := song.ParseReleasePeriod("", value)
+		if err != nil {
+			t.Fatalf("parse period %q error: %v", value, err)
+		}
+		return start, end
+	}
+
+	tests := []struct {
+		from, to string
+		year     string
+		want     []int
+	}{
+		{from: "2000s", to: "2000s", want: []int{all[2], year}},
+		{from: "2010s", want: []int{all[0], all[1], all[3], month}},
+		{to: "09.2012", want: []int{all[1], all[2], year}},
+		{from: "01.06.2009", to: "2012", want: []int{all[1], all[3]}},
+		{from: "2022", to: "31.03.2022", want: []int{month}},
+		{from: "2022", to: "30.03.2022", want: []int{}},
+		{year: "2009", want: []int{year}},
+		{from: "2010s", year: "2012", want: []int{all[1], all[3]}},
+	}
+
+	for _, tt := range tests {
+		filter := song.Filter{Year: tt.year}
+		if tt.from != "" {
+			filter.ReleasedFrom, _ = period(tt.from)
+		}
+		if tt.to != "" {
+			_, filter.ReleasedBefore = period(tt.to)
+		}
+		songs, err := s.GetAll(filter)
+		if err != nil {
+			t.Fatalf("get all error: %v", err)
+		}
+		if got := ids(songs); !equalIDs(got, tt.want) {
+			t.Errorf("from %q to %q year %q: got %v, want %v", tt.from, tt.to, tt.year, got, tt.want)
+		}
+	}
+}
+
+func testGetAllQuery(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+	noDate := mustAdd(t, s, "bones", "imagine dragons", "", "", "")
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{`group:dragons`, []int{all[0], all[3], noDate}},
+		{`dragons`, []int{all[0], all[3], noDate}},
+		{`group:dragons -link:false`, []int{all[3]}},
+		{`year:2012..2013 group:dragons`, []int{all[0], all[3]}},
+		{`year:..2012`, []int{all[1], all[2], all[3]}},
+		{`-year:2010s`, []int{all[2], noDate}},
+		{`released:09.2012..10.2012`, []int{all[1], all[3]}},
+		{`text:"like a diamond" OR song:demons`, []int{all[0], all[1]}},
+		{`-(muse OR rihanna) -text:new`, []int{all[0], noDate}},
+		{`NOT link:true AND (song:bones OR song:dia)`, []int{all[1], noDate}},
+		{`text:"%"`, []int{}},
+	}
+
+	for _, tt := range tests {
+		query, err := song.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("parse query %q error: %v", tt.query, err)
+		}
+		songs, err := s.GetAll(song.Filter{Query: query})
+		if err != nil {
+			t.Fatalf("get all %q error: %v", tt.query, err)
+		}
+		if got := ids(songs); !equalIDs(got, tt.want) {
+			t.Errorf("query %q: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func testGetAllSort(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+	noDate := mustAdd(t, s, "bones", "imagine dragons", "", "", "")
+
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{"", append(all, noDate)},
+		{"-id", []int{noDate, all[3], all[2], all[1], all[0]}},
+		{"releaseDate", []int{all[2], all[1], all[3], all[0], noDate}},
+		{"-releaseDate", []int{all[0], all[3], all[1], all[2], noDate}},
+		{"group,song", []int{noDate, all[0], all[3], all[2], all[1]}},
+		{"-group,-song", []int{all[1], all[2], all[3], all[0], noDate}},
+	}
+
+	for _, tt := range tests {
+		keys, err := song.ParseSort(tt.sort)
+		if err != nil {
+			t.Fatalf("parse sort %q error: %v", tt.sort, err)
+		}
+		songs, err := s.GetAll(song.Filter{Sort: keys})
+		if err != nil {
+			t.Fatalf("get all error: %v", err)
+		}
+		if got := ids(songs); !equalIDs(got, tt.want) {
+			t.Errorf("sort %q: got %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+// testGetAllSortCursor walks pages of two songs forward and back, songs without release date are on the last page
+func testGetAllSortCursor(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+	noDate := mustAdd(t, s, "bones", "imagine dragons", "", "", "")
+	keys, _ := song.ParseSort("-releaseDate")
+	want := [][]int{{all[0], all[3]}, {all[1], all[2]}, {noDate}}
+
+	filter := song.Filter{Limit: 2, Sort: keys}
+	var pages [][]*song.Song
+	for i := range want {
+		songs, err := s.GetAll(filter)
+		if err != nil {
+			t.Fatalf("get page %d error: %v", i, err)
+		}
+		if got := ids(songs); !equalIDs(got, want[i]) {
+			t.Fatalf("page %d: got %v, want %v", i, got, want[i])
+		}
+		pages = append(pages, songs)
+		filter.Cursor = song.NewCursor(songs[len(songs)-1], keys, false)
+	}
+
+	for i := len(want) - 1; i > 0; i-- {
+		filter.Cursor = song.NewCursor(pages[i][0], keys, true)
+		songs, err := s.GetAll(filter)
+		if err != nil {
+			t.Fatalf("get page %d backward error: %v", i-1, err)
+		}
+		if got := ids(songs); !equalIDs(got, want[i-1]) {
+			t.Errorf("page %d backward: got %v, want %v", i-1, got, want[i-1])
+		}
+	}
+}
+
+// testGetAllMultiKeyCursor walks pages of every size forward and back by encoded cursors, order has ties in group
+// and release date and songs without release date, pages together must be the whole ordered list
+func testGetAllMultiKeyCursor(t *testing.T, s song.Storage) {
+	fill(t, s)
+	mustAdd(t, s, "bones", "imagine dragons", "", "", "")
+	mustAdd(t, s, "believer", "imagine dragons", "2017", "", "")
+	mustAdd(t, s, "thunder", "imagine dragons", "2017", "", "")
+	mustAdd(t, s, "uprising", "muse", "", "", "")
+	keys, _ := song.ParseSort("group,-releaseDate,-song")
+
+	all, err := s.GetAll(song.Filter{Sort: keys})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	want := ids(all)
+
+	for limit := 1; limit <= len(want); limit++ {
+		filter := song.Filter{Limit: limit, Sort: keys}
+		var pages [][]*song.Song
+		got := make([]int, 0)
+		for len(pages) <= len(want) {
+			songs, err := s.GetAll(filter)
+			if err != nil {
+				t.Fatalf("limit %d: get page %d error: %v", limit, len(pages), err)
+			}
+			if len(songs) == 0 {
+				break
+			}
+			pages = append(pages, songs)
+			got = append(got, ids(songs)...)
+			filter.Cursor = decodeCursor(t, song.NewCursor(songs[len(songs)-1], keys, false), keys)
+		}
+		if !equalIDs(got, want) {
+			t.Fatalf("limit %d forward: got %v, want %v", limit, got, want)
+		}
+
+		for i := len(pages) - 1; i > 0; i-- {
+			filter.Cursor = decodeCursor(t, song.NewCursor(pages[i][0], keys, true), keys)
+			songs, err := s.GetAll(filter)
+			if err != nil {
+				t.Fatalf("limit %d: get page %d backward error: %v", limit, i-1, err)
+			}
+			if got, want := ids(songs), ids(pages[i-1]); !equalIDs(got, want) {
+				t.Errorf("limit %d page %d backward: got %v, want %v", limit, i-1, got, want)
+			}
+		}
+	}
+}
+
+// decodeCursor passes cursor through its string form like clients do
+func decodeCursor(t *testing.T, cursor *song.Cursor, keys []song.SortKey) *song.Cursor {
+	t.Helper()
+	res, err := song.DecodeCursor(song.EncodeCursor(cursor), keys)
+	if err != nil {
+		t.Fatalf("decode cursor error: %v", err)
+	}
+	return res
+}
+
+// testGetAllTamperedCursor checks values of cursor which has right form, but values are not of their fields
+func testGetAllTamperedCursor(t *testing.T, s song.Storage) {
+	fill(t, s)
+	keys, _ := song.ParseSort("-releaseDate,createdAt")
+	value := func(v string) *string {
+		return &v
+	}
+
+	tests := []struct {
+		name   string
+		values []*string
+	}{
+		{"bad id", []*string{value("28.01.2013"), value("2024-01-01T00:00:00Z"), value("1 OR 1=1")}},
+		{"bad release date", []*string{value("2013-01-28"), value("2024-01-01T00:00:00Z"), value("1")}},
+		{"bad time", []*string{value("28.01.2013"), value("yesterday"), value("1")}},
+		{"nil time", []*string{nil, nil, value("1")}},
+	}
+
+	for _, tt := range tests {
+		cursor := &song.Cursor{Values: tt.values, Sort: song.FormatSort(keys)}
+		_, err := s.GetAll(song.Filter{Limit: 2, Sort: keys, Cursor: cursor})
+		t.Run(tt.name, func(t *testing.T) {
+			checkValidation(t, err, "cursor")
+		})
+	}
+}
+
+func testUpdatePartial(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "old text", "old link")
+
+	if err := s.Update(id, "", "new text", ""); err != nil {
+		t.Fatalf("update text error: %v", err)
+	}
+	if err := s.Update(id, "05.10.2016", "", ""); err != nil {
+		t.Fatalf("update date error: %v", err)
+	}
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	want := song.Song{ID: id, Name: "demons", Group: "imagine dragons", ReleaseDate: "05.10.2016", Text: "new text", Link: "old link"}
+	if len(songs) != 1 || withoutTimes(songs[0]) != want {
+		t.Fatalf("after update: got %v, want %+v", songs, want)
+	}
+}
+
+func testUpdateErrors(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "", "")
+
+	if err := s.Update(id, "", "", ""); !errors.Is(err, song.ErrValidation) {
+		t.Errorf("empty update: got %v, want %v", err, song.ErrValidation)
+	}
+	checkValidation(t, s.Update(id, "2016-10-05", "", ""), "releaseDate")
+	if err := s.Update(id+100500, "", "text", ""); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("missing id update: got %v, want %v", err, song.ErrNotFound)
+	}
+}
+
+func testDelete(t *testing.T, s song.Storage) {
+	all := fill(t, s)
+
+	if err := s.Delete(all[1]); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	if err := s.Delete(all[1]); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("second delete: got %v, want %v", err, song.ErrNotFound)
+	}
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{all[0], all[2], all[3]}; !equalIDs(got, want) {
+		t.Errorf("after delete: got %v, want %v", got, want)
+	}
+}
+
+func testVersesParsed(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "[Intro]\r\nline1  \r\n\r\n \r\n[Chorus]\r\nline2\r\nline3\r\n\r\n\r\nline4\t", "")
+
+	verses, total, err := s.GetVerses(id, 0, 0)
+	if err != nil {
+		t.Fatalf("get verses error: %v", err)
+	}
+
+	want := []song.Verse{
+		{Position: 1, Kind: song.VerseKindIntro, Text: "line1"},
+		{Position: 2, Kind: song.VerseKindChorus, Text: "line2\nline3"},
+		{Position: 3, Kind: song.VerseKindVerse, Text: "line4"},
+	}
+	if total != len(want) || len(verses) != len(want) {
+		t.Fatalf("got %d of %d verses, want %d", len(verses), total, len(want))
+	}
+	for i := range want {
+		if *verses[i] != want[i] {
+			t.Errorf("verse %d: got %+v, want %+v", i+1, *verses[i], want[i])
+		}
+	}
+}
+
+func testVersesPagination(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "v1\n\nv2\n\nv3\n\nv4", "")
+	empty := mustAdd(t, s, "radioactive", "imagine dragons", "29.10.2012", "", "")
+
+	tests := []struct {
+		limit, offset int
+		want          []int
+	}{
+		{0, 0, []int{1, 2, 3, 4}},
+		{2, 0, []int{1, 2}},
+		{2, 1, []int{2, 3}},
+		{0, 3, []int{4}},
+		{10, 10, []int{}},
+	}
+
+	for _, tt := range tests {
+		verses, total, err := s.GetVerses(id, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("get verses error: %v", err)
+		}
+		got := make([]int, 0, len(verses))
+		for _, verse := range verses {
+			got = append(got, verse.Position)
+		}
+		if total != 4 || !equalIDs(got, tt.want) {
+			t.Errorf("limit %d offset %d: got positions %v of %d, want %v of 4", tt.limit, tt.offset, got, total, tt.want)
+		}
+	}
+
+	verses, total, err := s.GetVerses(empty, 0, 0)
+	if err != nil || total != 0 || len(verses) != 0 {
+		t.Errorf("song without text: got %v, %d, %v", verses, total, err)
+	}
+}
+
+func testVersesReplacedOnUpdate(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "v1\n\nv2\n\nv3", "")
+
+	if err := s.Update(id, "", "[Bridge]\nnew", ""); err != nil {
+		t.Fatalf("update text error: %v", err)
+	}
+
+	verses, total, err := s.GetVerses(id, 0, 0)
+	if err != nil {
+		t.Fatalf("get verses error: %v", err)
+	}
+	want := song.Verse{Position: 1, Kind: song.VerseKindBridge, Text: "new"}
+	if total != 1 || len(verses) != 1 || *verses[0] != want {
+		t.Errorf("after update: got %v of %d, want %+v", verses, total, want)
+	}
+}
+
+func testUpdateVerse(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "v1\n\n[Chorus]\nc1\n\nv2", "")
+
+	verse := &song.Verse{Position: 2, Text: "c2\nc3"}
+	if err := s.UpdateVerse(id, verse); err != nil {
+		t.Fatalf("update verse error: %v", err)
+	}
+	if verse.Kind != song.VerseKindChorus {
+		t.Errorf("empty kind must keep stored one, got %q", verse.Kind)
+	}
+
+	if err := s.UpdateVerse(id, &song.Verse{Position: 3, Kind: song.VerseKindBridge, Text: "b1"}); err != nil {
+		t.Fatalf("update verse kind error: %v", err)
+	}
+
+	got, err := s.GetVerse(id, 2)
+	if err != nil {
+		t.Fatalf("get verse error: %v", err)
+	}
+	if want := (song.Verse{Position: 2, Kind: song.VerseKindChorus, Text: "c2\nc3"}); *got != want {
+		t.Errorf("get verse: got %+v, want %+v", *got, want)
+	}
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if want := "v1\n\n[Chorus]\nc2\nc3\n\n[Bridge]\nb1"; len(songs) != 1 || songs[0].Text != want {
+		t.Errorf("song text must be rebuilt from verses, got %v, want %q", songs, want)
+	}
+}
+
+func testVerseNotFound(t *testing.T, s song.Storage) {
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "v1", "")
+
+	if _, err := s.GetVerse(id, 2); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("missing verse: got %v, want %v", err, song.ErrNotFound)
+	}
+	if _, err := s.GetVerse(id+100500, 1); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("missing song: got %v, want %v", err, song.ErrNotFound)
+	}
+	if err := s.UpdateVerse(id, &song.Verse{Position: 2, Text: "text"}); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("update missing verse: got %v, want %v", err, song.ErrNotFound)
+	}
+	if err := s.UpdateVerse(id+100500, &song.Verse{Position: 1, Text: "text"}); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("update verse of missing song: got %v, want %v", err, song.ErrNotFound)
+	}
+}
+
+// testRevisions is skipped for storages without revisions
+func testRevisions(t *testing.T, s song.Storage) {
+	revisioner, ok := s.(song.Revisioner)
+	if !ok {
+		t.Skip("storage does not keep revisions")
+	}
+
+	id := mustAdd(t, s, "demons", "imagine dragons", "", "", "")
+	editor := revisioner.As("editor")
+	if err := editor.Update(id, "28.01.2013", "old text", ""); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if err := editor.Update(id, "", "bad text", "link"); err != nil {
+		t.Fatalf("second update error: %v", err)
+	}
+
+	revisions, err := revisioner.GetRevisions(id, 0, 0)
+	if err != nil {
+		t.Fatalf("get revisions error: %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("got %d revisions, want 3", len(revisions))
+	}
+	created, updated := revisions[2], revisions[1]
+	if created.Action != song.RevisionCreate || created.Actor != song.ActorSystem || created.Previous != nil {
+		t.Errorf("create revision: got %+v", created)
+	}
+	wantChanges := []song.RevisionChange{
+		{Field: "releaseDate", OldValue: "", NewValue: "28.01.2013"},
+		{Field: "text", OldValue: "", NewValue: "old text"},
+	}
+	if updated.Action != song.RevisionUpdate || updated.Actor != "editor" || len(updated.Changes) != len(wantChanges) {
+		t.Fatalf("update revision: got %+v", updated)
+	}
+	for i, change := range updated.Changes {
+		if *change != wantChanges[i] {
+			t.Errorf("update change %d: got %+v, want %+v", i, *change, wantChanges[i])
+		}
+	}
+
+	page, err := revisioner.GetRevisions(id, 1, 1)
+	if err != nil || len(page) != 1 || page[0].ID != updated.ID {
+		t.Errorf("revisions page: got %v, %v, want revision %d", page, err, updated.ID)
+	}
+
+	restored, err := editor.(song.Revisioner).RestoreRevision(id, updated.ID)
+	if err != nil {
+		t.Fatalf("restore error: %v", err)
+	}
+	if restored.Action != song.RevisionRestore || restored.Actor != "editor" || *restored.State != *updated.State {
+		t.Errorf("restore revision: got %+v, want state %+v", restored, *updated.State)
+	}
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	want := song.Song{ID: id, Name: "demons", Group: "imagine dragons", ReleaseDate: "28.01.2013", Text: "old text"}
+	if len(songs) != 1 || withoutTimes(songs[0]) != want {
+		t.Errorf("after restore: got %v, want %+v", songs, want)
+	}
+	if verses, _, err := s.GetVerses(id, 0, 0); err != nil || len(verses) != 1 || verses[0].Text != "old text" {
+		t.Errorf("verses after restore: got %v, %v", verses, err)
+	}
+
+	if err := s.Delete(id); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	deleted, err := revisioner.GetRevisions(id, 1, 0)
+	if err != nil || len(deleted) != 1 || deleted[0].Action != song.RevisionDelete || deleted[0].State != nil {
+		t.Fatalf("revisions of deleted song: got %v, %v", deleted, err)
+	}
+	if _, err := revisioner.RestoreRevision(id, updated.ID); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("restore of deleted song: got %v, want %v", err, song.ErrNotFound)
+	}
+	if _, err := revisioner.RestoreRevision(id, deleted[0].ID); !errors.Is(err, song.ErrValidation) {
+		t.Errorf("restore of delete revision: got %v, want %v", err, song.ErrValidation)
+	}
+	if _, err := revisioner.GetRevision(id+100500, updated.ID); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("revision of other song: got %v, want %v", err, song.ErrNotFound)
+	}
+}
+
+// testRestoreRenamed checks that restore brings back song and group changed by patch,
+// restore of name taken by other song fails and keeps song unchanged
+func testRestoreRenamed(t *testing.T, s song.Storage) {
+	revisioner, ok := s.(song.Revisioner)
+	if !ok {
+		t.Skip("storage does not keep revisions")
+	}
+	patcher, ok := s.(song.Patcher)
+	if !ok {
+		t.Skip("storage does not patch songs")
+	}
+	value := func(v string) *string { return &v }
+
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "", "")
+	revisions, err := revisioner.GetRevisions(id, 0, 0)
+	if err != nil || len(revisions) != 1 {
+		t.Fatalf("revisions: got %v, %v, want create revision", revisions, err)
+	}
+	created := revisions[0]
+
+	err = patcher.Patch(id, &song.SongPatch{Name: value("demons (live)"), Group: value("dragons")})
+	if err != nil {
+		t.Fatalf("patch error: %v", err)
+	}
+	restored, err := revisioner.RestoreRevision(id, created.ID)
+	if err != nil {
+		t.Fatalf("restore error: %v", err)
+	}
+	if *restored.State != *created.State {
+		t.Errorf("restore revision state: got %+v, want %+v", *restored.State, *created.State)
+	}
+	item, err := s.GetSong(id)
+	if err != nil || item.Name != "demons" || item.Group != "imagine dragons" {
+		t.Fatalf("after restore: got %+v, %v, want demons of imagine dragons", item, err)
+	}
+
+	//the old name is taken by other song after rename
+	err = patcher.Patch(id, &song.SongPatch{Name: value("demons (live)")})
+	if err != nil {
+		t.Fatalf("patch error: %v", err)
+	}
+	mustAdd(t, s, "demons", "imagine dragons", "", "", "")
+	if _, err := revisioner.RestoreRevision(id, created.ID); !errors.Is(err, song.ErrConflict) {
+		t.Errorf("restore of taken name: got %v, want %v", err, song.ErrConflict)
+	}
+	item, err = s.GetSong(id)
+	if err != nil || item.Name != "demons (live)" || item.Group != "imagine dragons" {
+		t.Errorf("after failed restore: got %+v, %v, want demons (live) of imagine dragons", item, err)
+	}
+}
+
+func testTrash(t *testing.T, s song.Storage) {
+	trash, ok := s.(song.Trash)
+	if !ok {
+		t.Skip("storage does not keep deleted songs")
+	}
+
+	all := fill(t, s)
+	for _, id := range all[:2] {
+		if err := s.Delete(id); err != nil {
+			t.Fatalf("delete error: %v", err)
+		}
+	}
+
+	trashed, err := trash.GetTrash(0, 0)
+	if err != nil {
+		t.Fatalf("get trash error: %v", err)
+	}
+	if len(trashed) != 2 || trashed[0].ID != all[1] || trashed[1].ID != all[0] || trashed[0].DeletedAt.IsZero() {
+		t.Fatalf("trash: got %v, want songs %v latest first", trashed, all[:2])
+	}
+	if err := s.Update(all[0], "", "text", ""); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("update of trashed song: got %v, want %v", err, song.ErrNotFound)
+	}
+	if _, _, err := s.GetVerses(all[0], 0, 0); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("verses of trashed song: got %v, want %v", err, song.ErrNotFound)
+	}
+	if _, err := s.GetSong(all[0]); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("get trashed song: got %v, want %v", err, song.ErrNotFound)
+	}
+
+	//name of trashed song can be used again, then trashed song cannot be restored
+	again := mustAdd(t, s, "demons", "imagine dragons", "", "", "")
+	if err := trash.RestoreTrashed(all[0]); !errors.Is(err, song.ErrConflict) {
+		t.Errorf("restore of reused name: got %v, want %v", err, song.ErrConflict)
+	}
+	if err := trash.RestoreTrashed(all[1]); err != nil {
+		t.Fatalf("restore error: %v", err)
+	}
+	if err := trash.RestoreTrashed(all[1]); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("second restore: got %v, want %v", err, song.ErrNotFound)
+	}
+
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{all[1], all[2], all[3], again}; !equalIDs(got, want) {
+		t.Errorf("after restore: got %v, want %v", got, want)
+	}
+
+	if err := trash.Purge(all[2]); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("purge of song not in trash: got %v, want %v", err, song.ErrNotFound)
+	}
+	if err := s.Delete(all[2]); err != nil {
+		t.Fatalf("delete error: %v", err)
+	}
+	if err := trash.Purge(all[2]); err != nil {
+		t.Fatalf("purge error: %v", err)
+	}
+
+	purged, err := trash.PurgeTrash(time.Now().Add(time.Hour))
+	if err != nil || purged != 1 {
+		t.Errorf("purge trash: got %d, %v, want 1 song", purged, err)
+	}
+	if trashed, err := trash.GetTrash(0, 0); err != nil || len(trashed) != 0 {
+		t.Errorf("trash after purge: got %v, %v", trashed, err)
+	}
+}
+
+func testPatch(t *testing.T, s song.Storage) {
+	patcher, ok := s.(song.Patcher)
+	if !ok {
+		t.Skip("storage does not patch songs")
+	}
+
+	all := fill(t, s)
+	value := func(v string) *string { return &v }
+
+	//empty values clear release date, text and link
+	err := patcher.Patch(all[2], &song.SongPatch{ReleaseDate: value(""), Text: value(""), Link: value("")})
+	if err != nil {
+		t.Fatalf("patch error: %v", err)
+	}
+	item, err := s.GetSong(all[2])
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	want := song.Song{ID: all[2], Name: "supermassive black hole", Group: "muse"}
+	if withoutTimes(item) != want {
+		t.Errorf("after clear: got %+v, want %+v", *item, want)
+	}
+	if verses, total, err := s.GetVerses(all[2], 0, 0); err != nil || total != 0 || len(verses) != 0 {
+		t.Errorf("verses after clear: got %v, %d, %v", verses, total, err)
+	}
+
+	err = patcher.Patch(all[2], &song.SongPatch{Name: value("uprising"), ReleaseDate: value("09.2009")})
+	if err != nil {
+		t.Fatalf("rename error: %v", err)
+	}
+	item, err = s.GetSong(all[2])
+	if err != nil || item.Name != "uprising" || item.ReleaseDate != "09.2009" {
+		t.Errorf("after rename: got %+v, %v", item, err)
+	}
+
+	if err := patcher.Patch(all[3], &song.SongPatch{Name: value("demons")}); !errors.Is(err, song.ErrConflict) {
+		t.Errorf("rename to existing song: got %v, want %v", err, song.ErrConflict)
+	}
+	if err := patcher.Patch(all[0], &song.SongPatch{Group: value("muse")}); err != nil {
+		t.Fatalf("move to other group error: %v", err)
+	}
+	songs, err := s.GetAll(song.Filter{Group: "muse"})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{all[0], all[2]}; !equalIDs(got, want) {
+		t.Errorf("songs of new group: got %v, want %v", got, want)
+	}
+
+	if err := patcher.Patch(all[1], &song.SongPatch{ReleaseDate: value("2013-01-28")}); !errors.Is(err, song.ErrValidation) {
+		t.Errorf("bad date: got %v, want %v", err, song.ErrValidation)
+	}
+	if err := patcher.Patch(100500, &song.SongPatch{Text: value("text")}); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("patch of missing song: got %v, want %v", err, song.ErrNotFound)
+	}
+}
+
+func testVersion(t *testing.T, s song.Storage) {
+	versioner, ok := s.(song.Versioner)
+	if !ok {
+		t.Skip("storage does not keep song versions")
+	}
+
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "line1\n\nline2", "")
+	item, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if item.Version != 1 || item.ETag != `"1"` {
+		t.Fatalf("new song: got version %d, ETag %s", item.Version, item.ETag)
+	}
+
+	err = versioner.IfVersion(item.Version).Update(id, "", "", "some link")
+	if err != nil {
+		t.Fatalf("update with current version error: %v", err)
+	}
+	updated, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if updated.Version != item.Version+1 {
+		t.Errorf("after update: got version %d, want %d", updated.Version, item.Version+1)
+	}
+
+	//storage with old version changes nothing
+	stale := versioner.IfVersion(item.Version)
+	if err := stale.Update(id, "", "other text", ""); !errors.Is(err, song.ErrPreconditionFailed) {
+		t.Errorf("update with old version: got %v, want %v", err, song.ErrPreconditionFailed)
+	}
+	if err := stale.UpdateVerse(id, &song.Verse{Position: 1, Text: "line3"}); !errors.Is(err, song.ErrPreconditionFailed) {
+		t.Errorf("update verse with old version: got %v, want %v", err, song.ErrPreconditionFailed)
+	}
+	if err := stale.Delete(id); !errors.Is(err, song.ErrPreconditionFailed) {
+		t.Errorf("delete with old version: got %v, want %v", err, song.ErrPreconditionFailed)
+	}
+	if patcher, ok := stale.(song.Patcher); ok {
+		text := "other text"
+		if err := patcher.Patch(id, &song.SongPatch{Text: &text}); !errors.Is(err, song.ErrPreconditionFailed) {
+			t.Errorf("patch with old version: got %v, want %v", err, song.ErrPreconditionFailed)
+		}
+	}
+	if revisioner, ok := stale.(song.Revisioner); ok {
+		revisions, err := revisioner.GetRevisions(id, 0, 0)
+		if err != nil || len(revisions) == 0 {
+			t.Fatalf("revisions: got %v, %v", revisions, err)
+		}
+		created := revisions[len(revisions)-1]
+		if _, err := revisioner.RestoreRevision(id, created.ID); !errors.Is(err, song.ErrPreconditionFailed) {
+			t.Errorf("restore with old version: got %v, want %v", err, song.ErrPreconditionFailed)
+		}
+	}
+
+	item, err = s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+	if *item != *updated {
+		t.Errorf("after failed changes: got %+v, want %+v", *item, *updated)
+	}
+
+	if err := versioner.IfVersion(updated.Version).Delete(id); err != nil {
+		t.Fatalf("delete with current version error: %v", err)
+	}
+	if err := stale.Delete(id); !errors.Is(err, song.ErrNotFound) {
+		t.Errorf("delete of deleted song: got %v, want %v", err, song.ErrNotFound)
+	}
+}
+
+// testVersionNoChange checks that changes which set stored values keep version and ETag of song
+func testVersionNoChange(t *testing.T, s song.Storage) {
+	if _, ok := s.(song.Versioner); !ok {
+		t.Skip("storage does not keep song versions")
+	}
+
+	id := mustAdd(t, s, "demons", "imagine dragons", "28.01.2013", "line1\n\nline2", "some link")
+	item, err := s.GetSong(id)
+	if err != nil {
+		t.Fatalf("get song error: %v", err)
+	}
+
+	changes := []struct {
+		name   string
+		change func() error
+	}{
+		{"update", func() error { return s.Update(id, "28.01.2013", "line1\n\nline2", "some link") }},
+		{"update verse", func() error { return s.UpdateVerse(id, &song.Verse{Position: 2, Text: "line2"}) }},
+		{"patch", func() error {
+			patcher, ok := s.(song.Patcher)
+			if !ok {
+				return nil
+			}
+			name, link := "demons", "some link"
+			return patcher.Patch(id, &song.SongPatch{Name: &name, Link: &link})
+		}},
+	}
+
+	for _, tt := range changes {
+		if err := tt.change(); err != nil {
+			t.Fatalf("%s error: %v", tt.name, err)
+		}
+		got, err := s.GetSong(id)
+		if err != nil {
+			t.Fatalf("get song error: %v", err)
+		}
+		if got.Version != item.Version || !got.UpdatedAt.Equal(item.UpdatedAt) {
+			t.Errorf("after %s without changes: got version %d, updated at %s, want %d, %s",
+				tt.name, got.Version, got.UpdatedAt, item.Version, item.UpdatedAt)
+		}
+	}
+}
+
+func testAddWithEnrichment(t *testing.T, s song.Storage) {
+	queue, ok := s.(song.EnrichmentQueue)
+	if !ok {
+		t.Skip("storage does not keep enrichment jobs")
+	}
+
+	id, enrichment, err := queue.AddWithEnrichment("demons", "imagine dragons", "", "", "")
+	if err != nil {
+		t.Fatalf("add with enrichment error: %v", err)
+	}
+	if enrichment.SongID != id || enrichment.Status != song.EnrichmentPending {
+		t.Errorf("enrichment: got %+v, want pending job of song %d", *enrichment, id)
+	}
+	stored, err := queue.GetEnrichment(id)
+	if err != nil || stored.Status != song.EnrichmentPending {
+		t.Errorf("stored enrichment: got %+v, %v", stored, err)
+	}
+	if _, err := s.GetSong(id); err != nil {
+		t.Errorf("get song error: %v", err)
+	}
+
+	//nothing is saved when song is not added
+	if _, _, err := queue.AddWithEnrichment("demons", "imagine dragons", "", "", ""); !errors.Is(err, song.ErrConflict) {
+		t.Errorf("add duplicate: got %v, want %v", err, song.ErrConflict)
+	}
+	if _, _, err := queue.AddWithEnrichment("radioactive", "imagine dragons", "2013-01-28", "", ""); !errors.Is(err, song.ErrValidation) {
+		t.Errorf("add with bad date: got %v, want %v", err, song.ErrValidation)
+	}
+	songs, err := s.GetAll(song.Filter{})
+	if err != nil {
+		t.Fatalf("get all error: %v", err)
+	}
+	if got, want := ids(songs), []int{id}; !equalIDs(got, want) {
+		t.Errorf("songs after failed adds: got %v, want %v", got, want)
+	}
+}
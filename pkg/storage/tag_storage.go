@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"SongLibrary/pkg/tag"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+func errTagNotFound(id int) error {
+	return fmt.Errorf("tag with id [%d]: %w", id, song.ErrNotFound)
+}
+
+func (s *Storage) GetTags(limit, offset int, kind string) ([]*tag.Tag, error) {
+
+	//songs in trash are not counted
+	query := `SELECT tags.id, tags.name, tags.kind, count(songs.id) FROM tags
+	LEFT JOIN song_tags ON song_tags.tag_id = tags.id
+	LEFT JOIN songs ON songs.id = song_tags.song_id AND songs.deleted_at IS NULL `
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+
+	if kind != "" {
+		query += fmt.Sprintf("WHERE tags.kind = $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, kind)
+	}
+
+	query += "GROUP BY tags.id ORDER BY count(songs.id) DESC, tags.name "
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get tags query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+
+	tags, err := scanTags(rows)
+	if err != nil {
+		log.Printf("method get tags scan error: [%s], query: [%s]\n", err.Error(), query)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *Storage) DeleteTag(id int) error {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM tags WHERE id = $1`, id,
+	)
+	if err != nil {
+		log.Printf("method delete tag query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errTagNotFound(id)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetSongTags(songID int) ([]*tag.Tag, error) {
+
+	var exists bool
+	err := s.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`, songID).Scan(&exists)
+	if err != nil {
+		log.Printf("method get song tags query error: [%s], id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+	if !exists {
+		return nil, errSongNotFound(songID)
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT tags.id, tags.name, tags.kind, (SELECT count(*) FROM song_tags counted
+		JOIN songs ON songs.id = counted.song_id AND songs.deleted_at IS NULL WHERE counted.tag_id = tags.id)
+	FROM song_tags JOIN tags ON tags.id = song_tags.tag_id
+	WHERE song_tags.song_id = $1
+	ORDER BY tags.kind, tags.name`, songID,
+	)
+	if err != nil {
+		log.Printf("method get song tags query error: [%s], id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	tags, err := scanTags(rows)
+	if err != nil {
+		log.Printf("method get song tags scan error: [%s], id: [%d]\n", err.Error(), songID)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// AttachTag finds tag by name ignoring case or creates it with item kind, item gets stored id, name, kind and songs count
+func (s *Storage) AttachTag(songID int, item *tag.Tag) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method attach tag begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	//song is locked, so it is not moved to trash before commit
+	var exists bool
+	err = tx.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL FOR SHARE)`, songID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("method attach tag song query error: [%s], song id: [%d]\n", err.Error(), songID)
+		return err
+	}
+	if !exists {
+		return errSongNotFound(songID)
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO tags(name,kind) VALUES($1,$2)
+	ON CONFLICT ((lower(name))) DO UPDATE SET name = tags.name
+	RETURNING id, name, kind`,
+		item.Name, item.Kind,
+	).Scan(&item.ID, &item.Name, &item.Kind)
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("tag [%s]", item.Name))
+		if !isMapped(err) {
+			log.Printf("method attach tag upsert error: [%s], tag: [%s]\n", err.Error(), item.Name)
+		}
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO song_tags(song_id,tag_id) VALUES($1,$2) ON CONFLICT DO NOTHING`,
+		songID, item.ID,
+	)
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("song with id [%d]", songID))
+		if !isMapped(err) {
+			log.Printf("method attach tag query error: [%s], song id: [%d], tag id: [%d]\n", err.Error(), songID, item.ID)
+		}
+		return err
+	}
+
+	err = tx.QueryRow(
+		`SELECT count(*) FROM song_tags JOIN songs ON songs.id = song_tags.song_id AND songs.deleted_at IS NULL
+	WHERE song_tags.tag_id = $1`, item.ID,
+	).Scan(&item.SongsCount)
+	if err != nil {
+		log.Printf("method attach tag count error: [%s], tag id: [%d]\n", err.Error(), item.ID)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method attach tag commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) DetachTag(songID int, name string) error {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM song_tags USING tags
+	WHERE song_tags.tag_id = tags.id AND song_tags.song_id = $1 AND lower(tags.name) = lower($2)`,
+		songID, name,
+	)
+	if err != nil {
+		log.Printf("method detach tag query error: [%s], song id: [%d], tag: [%s]\n", err.Error(), songID, name)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return fmt.Errorf("tag [%s] of song with id [%d]: %w", name, songID, song.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanTags(rows *sql.Rows) ([]*tag.Tag, error) {
+
+	defer rows.Close()
+
+	tags := make([]*tag.Tag, 0)
+	for rows.Next() {
+		item := &tag.Tag{}
+		err := rows.Scan(&item.ID, &item.Name, &item.Kind, &item.SongsCount)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, item)
+	}
+
+	return tags, rows.Err()
+}
+
+// uniqueTagNames lowers names and drops duplicates, so number of names can be compared with number of matched tags
+func uniqueTagNames(names []string) []string {
+
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
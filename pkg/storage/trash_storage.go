@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"fmt"
+	"log"
+	"time"
+)
+
+func (s *Storage) GetTrash(limit, offset int) ([]*song.TrashedSong, error) {
+
+	query := `SELECT songs.id, song_name, groups.name, release_date, release_date_precision, text, link, songs.created_at, songs.updated_at, songs.version, songs.deleted_at
+	FROM songs JOIN groups ON groups.id = songs.group_id
+	WHERE songs.deleted_at IS NOT NULL
+	ORDER BY songs.deleted_at DESC, songs.id DESC `
+	placeholderNum := 1
+	args := make([]interface{}, 0)
+
+	if limit > 0 {
+		query += fmt.Sprintf("LIMIT $%d ", placeholderNum)
+		placeholderNum++
+		args = append(args, limit)
+	}
+
+	if offset > 0 {
+		query += fmt.Sprintf("OFFSET $%d ", placeholderNum)
+		args = append(args, offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		log.Printf("method get trash query error: [%s], query: [%s], args: [%v]\n", err.Error(), query, args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]*song.TrashedSong, 0)
+	for rows.Next() {
+		var deletedAt time.Time
+		item, err := scanSong(rows, &deletedAt)
+		if err != nil {
+			log.Printf("method get trash scan error: [%s], query: [%s]\n", err.Error(), query)
+			return nil, err
+		}
+		songs = append(songs, &song.TrashedSong{Song: *item, DeletedAt: deletedAt})
+	}
+
+	return songs, rows.Err()
+}
+
+func (s *Storage) RestoreTrashed(id int) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method restore trashed begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE songs SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id,
+	)
+	if err != nil {
+		err = pqError(err, fmt.Sprintf("song with id [%d]", id))
+		if !isMapped(err) {
+			log.Printf("method restore trashed query error: [%s], id: [%d]\n", err.Error(), id)
+		}
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errTrashedNotFound(id)
+	}
+
+	_, err = recordRevision(tx, id, song.RevisionRestore, s.actor, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method restore trashed commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) Purge(id int) error {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM songs WHERE id = $1 AND deleted_at IS NOT NULL`, id,
+	)
+	if err != nil {
+		log.Printf("method purge query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return errTrashedNotFound(id)
+	}
+
+	return nil
+}
+
+func (s *Storage) PurgeTrash(deletedBefore time.Time) (int, error) {
+
+	result, err := s.DB.Exec(
+		`DELETE FROM songs WHERE deleted_at < $1`, deletedBefore,
+	)
+	if err != nil {
+		log.Printf("method purge trash query error: [%s], deleted before: [%s]\n", err.Error(), deletedBefore)
+		return 0, err
+	}
+
+	num, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(num), nil
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+func (s *Storage) GetVerses(id, limit, offset int) ([]*song.Verse, int, error) {
+
+	var total int
+	err := s.DB.QueryRow(
+		`SELECT count(verses.position) FROM songs LEFT JOIN verses ON verses.song_id = songs.id
+	WHERE songs.id = $1 AND songs.deleted_at IS NULL
+	GROUP BY songs.id`, id,
+	).Scan(&total)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, 0, errSongNotFound(id)
+		}
+		log.Printf("method get verses count query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, 0, err
+	}
+
+	//NULL limit means no limit
+	var limitArg sql.NullInt64
+	if limit > 0 {
+		limitArg = sql.NullInt64{Int64: int64(limit), Valid: true}
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT position, kind, text FROM verses WHERE song_id = $1 ORDER BY position LIMIT $2 OFFSET $3`,
+		id, limitArg, offset,
+	)
+	if err != nil {
+		log.Printf("method get verses query error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, 0, err
+	}
+
+	verses, err := scanVerses(rows)
+	if err != nil {
+		log.Printf("method get verses scan error: [%s], id: [%d]\n", err.Error(), id)
+		return nil, 0, err
+	}
+
+	return verses, total, nil
+}
+
+func (s *Storage) GetVerse(id, position int) (*song.Verse, error) {
+
+	verse := &song.Verse{}
+	err := s.DB.QueryRow(
+		`SELECT position, kind, verses.text FROM verses JOIN songs ON songs.id = verses.song_id
+	WHERE song_id = $1 AND position = $2 AND songs.deleted_at IS NULL`, id, position,
+	).Scan(&verse.Position, &verse.Kind, &verse.Text)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.verseNotFound(id, position)
+		}
+		log.Printf("method get verse query error: [%s], id: [%d], position: [%d]\n", err.Error(), id, position)
+		return nil, err
+	}
+
+	return verse, nil
+}
+
+func (s *Storage) UpdateVerse(id int, verse *song.Verse) error {
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("method update verse begin transaction error: [%s]\n", err.Error())
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := songState(tx, id)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		return errSongNotFound(id)
+	}
+	err = checkVersion(tx, id, s.ifVersion)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(
+		`UPDATE verses SET kind = coalesce(NULLIF($1, ''), kind), text = $2
+	WHERE song_id = $3 AND position = $4
+	RETURNING kind`,
+		verse.Kind, verse.Text, id, verse.Position,
+	).Scan(&verse.Kind)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.verseNotFound(id, verse.Position)
+		}
+		err = pqError(err, "verse")
+		if !isMapped(err) {
+			log.Printf("method update verse query error: [%s], id: [%d], position: [%d]\n", err.Error(), id, verse.Position)
+		}
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT position, kind, text FROM verses WHERE song_id = $1 ORDER BY position`, id)
+	if err != nil {
+		log.Printf("method update verse select query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	verses, err := scanVerses(rows)
+	if err != nil {
+		log.Printf("method update verse scan error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE songs SET text = $1 WHERE id = $2`, song.JoinVerses(verses), id)
+	if err != nil {
+		log.Printf("method update verse song text query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	_, err = recordRevision(tx, id, song.RevisionUpdate, s.actor, previous)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("method update verse commit error: [%s]\n", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// verseNotFound tells missing song from missing verse of existing song
+func (s *Storage) verseNotFound(id, position int) error {
+
+	var exists bool
+	err := s.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		log.Printf("song exists query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+	if !exists {
+		return errSongNotFound(id)
+	}
+
+	return errVerseNotFound(id, position)
+}
+
+// replaceVerses stores verses parsed from text instead of current verses of song
+func replaceVerses(tx *sql.Tx, id int, text string) error {
+
+	_, err := tx.Exec(`DELETE FROM verses WHERE song_id = $1`, id)
+	if err != nil {
+		log.Printf("replace verses delete query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	verses := song.ParseVerses(text)
+	if len(verses) == 0 {
+		return nil
+	}
+
+	positions := make([]int64, 0, len(verses))
+	kinds := make([]string, 0, len(verses))
+	texts := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		positions = append(positions, int64(verse.Position))
+		kinds = append(kinds, verse.Kind)
+		texts = append(texts, verse.Text)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO verses(song_id,position,kind,text)
+	SELECT $1, * FROM unnest($2::integer[], $3::text[], $4::text[])`,
+		id, pq.Array(positions), pq.Array(kinds), pq.Array(texts),
+	)
+	if err != nil {
+		log.Printf("replace verses insert query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+
+	return nil
+}
+
+func scanVerses(rows *sql.Rows) ([]*song.Verse, error) {
+
+	defer rows.Close()
+
+	verses := make([]*song.Verse, 0)
+	for rows.Next() {
+		verse := &song.Verse{}
+		err := rows.Scan(&verse.Position, &verse.Kind, &verse.Text)
+		if err != nil {
+			return nil, err
+		}
+		verses = append(verses, verse)
+	}
+
+	return verses, rows.Err()
+}
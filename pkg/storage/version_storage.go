@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"SongLibrary/pkg/song"
+	"database/sql"
+	"log"
+)
+
+// IfVersion returns storage which changes song only with given version, it shares connection pool with s
+func (s *Storage) IfVersion(version int) song.Storage {
+	res := *s
+	res.ifVersion = version
+	return &res
+}
+
+// checkVersion compares version of song locked in tx with expected one, zero expected version is not checked
+func checkVersion(tx *sql.Tx, id, expected int) error {
+
+	if expected == 0 {
+		return nil
+	}
+
+	var version int
+	err := tx.QueryRow(`SELECT version FROM songs WHERE id = $1`, id).Scan(&version)
+	if err != nil {
+		log.Printf("song version query error: [%s], id: [%d]\n", err.Error(), id)
+		return err
+	}
+	if version != expected {
+		return errVersionChanged(id, version)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package tag
+
+import (
+	"SongLibrary/pkg/song"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// @Summary Get tags list
+// @Description Get tags and genres with number of songs, the most used first
+// @Tags tags
+// @ID get-tags
+// @Param limit query int false "Limit" Default(0)
+// @Param offset query int false "Offset" Default(0)
+// @Param kind query string false "tag or genre, all kinds if empty"
+// @Produce json
+// @Success 200 {object} song.Response{response=[]tag.Tag}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/tags [get]
+func (th *TagHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	limit, offset, err := song.ReadPagination(r)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	kind := r.FormValue("kind")
+	if kind != "" && kind != KindTag && kind != KindGenre {
+		song.WriteError(w, r, song.NewValidationError("kind", "must be tag or genre"))
+		return
+	}
+
+	tags, err := th.Storage.GetTags(limit, offset, kind)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": tags,
+	})
+}
+
+// @Summary Delete tag
+// @Description Delete tag or genre, it is detached from all songs
+// @Tags tags
+// @ID delete-tag
+// @Param bodyJSON body string true "tag id" SchemaExample({"id":2})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/tags [delete]
+func (th *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+
+	tag := &Tag{}
+	err := song.ReadJSON(r, tag)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	err = th.Storage.DeleteTag(tag.ID)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("tag deleted, id: [%d], user agent: [%s], path: [%s], method: [%s]\n", tag.ID, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
+
+// @Summary Get song tags
+// @Description Get tags and genres of song
+// @Tags tags
+// @ID get-song-tags
+// @Param id path int true "song id"
+// @Produce json
+// @Success 200 {object} song.Response{response=[]tag.Tag}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/songs/{id}/tags [get]
+func (th *TagHandler) GetSongTags(w http.ResponseWriter, r *http.Request) {
+
+	songID, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	tags, err := th.Storage.GetSongTags(songID)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusOK, song.Response{
+		"response": tags,
+	})
+}
+
+// @Summary Attach tag to song
+// @Description Attach tag or genre to song by name, tag is created with given kind if it does not exist, attaching twice does nothing
+// @Tags tags
+// @ID attach-tag
+// @Param id path int true "song id"
+// @Param bodyJSON body string true "tag name is required, kind is tag or genre, tag by default" SchemaExample({"name":"alternative rock","kind":"genre"})
+// @Accept json
+// @Produce json
+// @Success 201 {object} song.Response{response=tag.Tag}
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/songs/{id}/tags [put]
+func (th *TagHandler) Attach(w http.ResponseWriter, r *http.Request) {
+
+	songID, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	tag := &Tag{}
+	err = song.ReadJSON(r, tag)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		song.WriteError(w, r, song.NewValidationError("name", "must be not empty"))
+		return
+	}
+
+	if tag.Kind == "" {
+		tag.Kind = KindTag
+	}
+	if tag.Kind != KindTag && tag.Kind != KindGenre {
+		song.WriteError(w, r, song.NewValidationError("kind", "must be tag or genre"))
+		return
+	}
+
+	err = th.Storage.AttachTag(songID, tag)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	song.WriteResponse(w, r, http.StatusCreated, song.Response{
+		"response": tag,
+	})
+}
+
+// @Summary Detach tag from song
+// @Description Detach tag or genre from song by name, tag itself is kept
+// @Tags tags
+// @ID detach-tag
+// @Param id path int true "song id"
+// @Param bodyJSON body string true "tag name" SchemaExample({"name":"alternative rock"})
+// @Accept json
+// @Success 200
+// @Failure 400 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 404 {object} song.Response{error=song.Response{timestamp=time,message=string,path=string}}
+// @Failure 500 "something bad with db or marshal/unmarshal data"
+// @Router /api/songs/{id}/tags [delete]
+func (th *TagHandler) Detach(w http.ResponseWriter, r *http.Request) {
+
+	songID, err := song.ReadPathID(r, "id")
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	tag := &Tag{}
+	err = song.ReadJSON(r, tag)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		song.WriteError(w, r, song.NewValidationError("name", "must be not empty"))
+		return
+	}
+
+	err = th.Storage.DetachTag(songID, tag.Name)
+	if err != nil {
+		song.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	log.Printf("tag detached from song, song id: [%d], tag: [%s], user agent: [%s], path: [%s], method: [%s]\n", songID, tag.Name, r.Header.Get("User-Agent"), r.URL.Path, r.Method)
+}
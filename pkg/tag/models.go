@@ -0,0 +1,27 @@
+package tag
+
+// Tag model info
+// @Description free-form tag or genre of songs, names are unique ignoring case
+type Tag struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	SongsCount int    `json:"songsCount"`
+}
+
+const (
+	KindTag   = "tag"
+	KindGenre = "genre"
+)
+
+type TagHandler struct {
+	Storage
+}
+
+type Storage interface {
+	GetTags(limit, offset int, kind string) ([]*Tag, error)
+	DeleteTag(id int) error
+	GetSongTags(songID int) ([]*Tag, error)
+	AttachTag(songID int, tag *Tag) error
+	DetachTag(songID int, name string) error
+}